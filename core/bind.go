@@ -0,0 +1,180 @@
+package core
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	timeType    = reflect.TypeFor[time.Time]()
+	bytesType   = reflect.TypeFor[[]byte]()
+	rowMapType  = reflect.TypeFor[map[string]any]()
+)
+
+// * struct, map[string]any or a scalar, alone or as a slice
+func bindTarget(target any) (reflect.Value, error) {
+	targetVal := reflect.ValueOf(target)
+	if targetVal.Kind() != reflect.Pointer || targetVal.IsNil() {
+		return reflect.Value{}, fmt.Errorf("target must be a pointer")
+	}
+
+	targetElem := targetVal.Elem()
+	typ := targetElem.Type()
+	if typ.Kind() == reflect.Slice && !isScalar(typ) {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Map && typ != rowMapType {
+		return reflect.Value{}, fmt.Errorf("map target must be map[string]any")
+	}
+	return targetElem, nil
+}
+
+// * single targets read one row and return sql.ErrNoRows when empty, slices append
+func bindRows(rows *sql.Rows, target reflect.Value) error {
+	typ := target.Type()
+
+	switch {
+	case isScalar(typ):
+		if !rows.Next() {
+			return noRows(rows)
+		}
+		return rows.Scan(target.Addr().Interface())
+	case typ == rowMapType:
+		cols, blobs, err := mapColumns(rows)
+		if err != nil {
+			return err
+		}
+		if !rows.Next() {
+			return noRows(rows)
+		}
+		row, err := scanMap(rows, cols, blobs)
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(row))
+		return nil
+	case typ.Kind() == reflect.Struct:
+		return find(rows, target)
+	}
+
+	elemType := typ.Elem()
+	switch {
+	case elemType.Kind() == reflect.Struct && !isScalar(elemType):
+		return findSlice(rows, target)
+	case elemType == rowMapType:
+		cols, blobs, err := mapColumns(rows)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			row, err := scanMap(rows, cols, blobs)
+			if err != nil {
+				return err
+			}
+			target.Set(reflect.Append(target, reflect.ValueOf(row)))
+		}
+		return rows.Err()
+	default:
+		return pluck(rows, target)
+	}
+}
+
+func noRows(rows *sql.Rows) error {
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return sql.ErrNoRows
+}
+
+// * time.Time, sql.Null* and other Scanners bind as one value, not as a struct
+func isScalar(typ reflect.Type) bool {
+	if typ == timeType || typ == bytesType || reflect.PointerTo(typ).Implements(scannerType) {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map:
+		return false
+	}
+	return true
+}
+
+// * first column of every row, []any values are normalized like scanMap
+func pluck(rows *sql.Rows, target reflect.Value) error {
+	blobs, err := blobColumns(rows)
+	if err != nil {
+		return err
+	}
+	if len(blobs) != 1 {
+		return fmt.Errorf("slice target of %s requires a single column, got %d", target.Type().Elem(), len(blobs))
+	}
+
+	elemType := target.Type().Elem()
+	for rows.Next() {
+		item := reflect.New(elemType)
+		if err := rows.Scan(item.Interface()); err != nil {
+			return err
+		}
+		if elemType.Kind() == reflect.Interface {
+			item.Elem().Set(reflect.ValueOf(normalize(item.Elem().Interface(), blobs[0])).Convert(elemType))
+		}
+		target.Set(reflect.Append(target, item.Elem()))
+	}
+	return rows.Err()
+}
+
+// * read once per query, scanMap reuses them for every row
+func mapColumns(rows *sql.Rows) ([]string, []bool, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	blobs, err := blobColumns(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cols, blobs, nil
+}
+
+func scanMap(rows *sql.Rows, cols []string, blobs []bool) (map[string]any, error) {
+	values := make([]any, len(cols))
+	dest := make([]any, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]any, len(cols))
+	for i, col := range cols {
+		row[col] = normalize(values[i], blobs[i])
+	}
+	return row, nil
+}
+
+// * true for columns declared BLOB or without a declared type, their bytes are kept
+func blobColumns(rows *sql.Rows) ([]bool, error) {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := make([]bool, len(types))
+	for i, t := range types {
+		name := strings.ToUpper(t.DatabaseTypeName())
+		blobs[i] = name == "" || strings.Contains(name, "BLOB")
+	}
+	return blobs, nil
+}
+
+// * TEXT stored through a []byte argument comes back as bytes, hand it out as string
+func normalize(v any, blob bool) any {
+	if b, ok := v.([]byte); ok && !blob {
+		return string(b)
+	}
+	return v
+}
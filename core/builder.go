@@ -1,8 +1,10 @@
 package core
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -16,9 +18,74 @@ func (b *Builder) Raw() *sql.DB {
 	return b.DB
 }
 
+// * returns a new builder per query, the receiver is never modified
 func (b *Builder) Table(name string) *Builder {
-	b.TableName = &name
-	return b
+	next := b.Clone()
+	next.TableName = &name
+	return next
+}
+
+// * deep copy so derived queries never share slices with the base
+func (b *Builder) Clone() *Builder {
+	next := b.session()
+	next.SelectList = slices.Clone(b.SelectList)
+	next.ExprList = slices.Clone(b.ExprList)
+	next.UpdateList = slices.Clone(b.UpdateList)
+	next.WhereList = slices.Clone(b.WhereList)
+	next.WhereArgs = slices.Clone(b.WhereArgs)
+	next.JoinList = slices.Clone(b.JoinList)
+	next.ReturningList = slices.Clone(b.ReturningList)
+	next.OrderByList = slices.Clone(b.OrderByList)
+	next.GroupByList = slices.Clone(b.GroupByList)
+	next.HavingList = slices.Clone(b.HavingList)
+	next.HavingArgs = slices.Clone(b.HavingArgs)
+	next.WithTotal = b.WithTotal
+	next.TrashedMode = b.TrashedMode
+	next.SkipScopes = slices.Clone(b.SkipScopes)
+	next.WithContext = b.WithContext
+	next.WithBind = b.WithBind
+	next.Error = slices.Clone(b.Error)
+
+	if b.TableName != nil {
+		name := *b.TableName
+		next.TableName = &name
+	}
+	if b.ConflictMode != nil {
+		mode := *b.ConflictMode
+		next.ConflictMode = &mode
+	}
+	if b.WithLimit != nil {
+		limit := *b.WithLimit
+		next.WithLimit = &limit
+	}
+	if b.WithOffset != nil {
+		offset := *b.WithOffset
+		next.WithOffset = &offset
+	}
+	if b.WithUpsert != nil {
+		next.WithUpsert = &upsert{
+			Target:    slices.Clone(b.WithUpsert.Target),
+			Nothing:   b.WithUpsert.Nothing,
+			SetList:   slices.Clone(b.WithUpsert.SetList),
+			SetArgs:   slices.Clone(b.WithUpsert.SetArgs),
+			WhereList: slices.Clone(b.WithUpsert.WhereList),
+			WhereArgs: slices.Clone(b.WithUpsert.WhereArgs),
+		}
+	}
+	return next
+}
+
+// * connection-level fields only, shared by every query started from b
+func (b *Builder) session() *Builder {
+	return &Builder{
+		DB:     b.DB,
+		Cache:  b.Cache,
+		Schema: b.Schema,
+		Retry:  b.Retry,
+		Queue:  b.Queue,
+		Tables: b.Tables,
+		Hooks:  slices.Clone(b.Hooks),
+	}
 }
 
 func (b *Builder) Create(columns ...Column) error {
@@ -85,65 +152,98 @@ func buildColumn(c Column) string {
 	return strings.Join(parts, " ")
 }
 
+// * tables registered with SoftDelete are stamped instead, see ForceDelete
 func (b *Builder) Delete(force ...bool) (int64, error) {
+	if column, ok := b.softDeleteColumn(); ok {
+		if len(b.WhereList) == 0 && (len(force) == 0 || !force[0]) {
+			defer builderClear(b)
+			return 0, fmt.Errorf("delete without where need to use force = true")
+		}
+		b.UpdateList = append(b.UpdateList, fmt.Sprintf("%s = %s", quote(column), b.deletedAt()))
+		return b.Update()
+	}
+	return b.delete(force...)
+}
+
+func (b *Builder) delete(force ...bool) (int64, error) {
 	defer builderClear(b)
 
 	if len(b.Error) > 0 {
 		return 0, b.Error[0]
 	}
 
+	query, values, err := deleteBuilder(b, force...)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(b.ReturningList) > 0 {
+		return b.queryReturning(query, values...)
+	}
+
+	result, err := b.ExecAutoAsignContext(query, values...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func deleteBuilder(b *Builder, force ...bool) (string, []any, error) {
 	if len(b.WhereList) == 0 && (len(force) == 0 || !force[0]) {
-		return 0, fmt.Errorf("delete without where need to use force = true")
+		return "", nil, fmt.Errorf("delete without where need to use force = true")
 	}
 
 	if b.TableName == nil {
-		return 0, fmt.Errorf("table name is required")
+		return "", nil, fmt.Errorf("table name is required")
 	}
 
 	if err := ValidateColumn(*b.TableName); err != nil {
-		return 0, err
+		return "", nil, err
 	}
 
 	if len(b.JoinList) > 0 {
-		return 0, fmt.Errorf("SQLite DELETE does not support JOIN")
+		return "", nil, fmt.Errorf("SQLite DELETE does not support JOIN")
 	}
 
 	if len(b.GroupByList) > 0 {
-		return 0, fmt.Errorf("SQLite DELETE does not support GROUP BY")
+		return "", nil, fmt.Errorf("SQLite DELETE does not support GROUP BY")
 	}
 
 	if len(b.HavingList) > 0 || len(b.HavingArgs) > 0 {
-		return 0, fmt.Errorf("SQLite DELETE does not support HAVING")
+		return "", nil, fmt.Errorf("SQLite DELETE does not support HAVING")
 	}
 
 	if len(b.OrderByList) > 0 {
-		return 0, fmt.Errorf("SQLite DELETE does not support ORDER BY")
+		return "", nil, fmt.Errorf("SQLite DELETE does not support ORDER BY")
 	}
 
 	if b.WithLimit != nil || b.WithOffset != nil {
-		return 0, fmt.Errorf("SQLite DELETE does not support LIMIT / OFFSET")
+		return "", nil, fmt.Errorf("SQLite DELETE does not support LIMIT / OFFSET")
 	}
 
 	var sb strings.Builder
 	sb.WriteString("DELETE FROM ")
 	sb.WriteString(quote(*b.TableName))
-	sb.WriteString(b.buildWhere())
-
-	result, err := b.ExecAutoAsignContext(sb.String(), b.WhereArgs...)
+	where, err := b.buildWhere()
 	if err != nil {
-		return 0, err
+		return "", nil, err
 	}
+	sb.WriteString(where)
 
-	return result.RowsAffected()
+	return sb.String(), b.whereArgs(), nil
 }
 
 func builderClear(b *Builder) {
 	b.SelectList = []string{}
+	b.ExprList = []string{}
 	b.UpdateList = []string{}
 	b.WhereList = []Where{}
 	b.WhereArgs = []any{}
 	b.JoinList = []Join{}
 	b.ConflictMode = nil
+	b.WithUpsert = nil
+	b.ReturningList = []string{}
 	b.OrderByList = []string{}
 	b.GroupByList = []string{}
 	b.HavingList = []Where{}
@@ -151,21 +251,118 @@ func builderClear(b *Builder) {
 	b.WithLimit = nil
 	b.WithOffset = nil
 	b.WithTotal = false
+	b.TrashedMode = excludeTrashed
+	b.SkipScopes = nil
 	b.WithContext = nil
 	b.WithBind = nil
 	b.Error = []error{}
 }
 
 func (b *Builder) ExecAutoAsignContext(query string, args ...any) (sql.Result, error) {
+	ctx, start := b.beforeQuery(query, args)
+
 	var result sql.Result
 	var err error
-	if b.WithContext != nil {
-		result, err = b.DB.ExecContext(b.WithContext, query, args...)
+	if b.Queue != nil && isGroupable(query) {
+		result, err = b.Queue.exec(ctx, query, args)
 	} else {
-		result, err = b.DB.Exec(query, args...)
+		err = b.Retry.do(ctx, func() error {
+			var err error
+			if b.Cache != nil && !isSchemaChange(query) {
+				var entry *stmtEntry
+				if entry, err = b.Cache.acquire(ctx, query); err == nil {
+					result, err = entry.stmt.ExecContext(ctx, args...)
+					b.Cache.release(entry)
+				}
+			} else {
+				result, err = b.DB.ExecContext(ctx, query, args...)
+			}
+			return wrapError(err)
+		})
+	}
+
+	affected := int64(-1)
+	if err == nil {
+		affected, _ = result.RowsAffected()
 	}
-	if err != nil && strings.Contains(err.Error(), "readonly") {
-		return nil, fmt.Errorf("write operation on read-only db: %w", err)
+	b.afterQuery(ctx, query, args, start, affected, err)
+
+	if err != nil {
+		return nil, err
 	}
+	b.schemaChanged(query)
 	return result, nil
 }
+
+func (b *Builder) queryAutoAsignContext(query string, args ...any) (*sql.Rows, error) {
+	ctx, start := b.beforeQuery(query, args)
+
+	var rows *sql.Rows
+	err := b.Retry.do(ctx, func() error {
+		var err error
+		if b.Cache != nil {
+			var entry *stmtEntry
+			if entry, err = b.Cache.acquire(ctx, query); err == nil {
+				rows, err = entry.stmt.QueryContext(ctx, args...)
+				b.Cache.release(entry)
+			}
+		} else {
+			rows, err = b.DB.QueryContext(ctx, query, args...)
+		}
+		return wrapError(err)
+	})
+	b.afterQuery(ctx, query, args, start, -1, err)
+
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (b *Builder) queryRowAutoAsignContext(query string, args ...any) *sql.Row {
+	ctx, start := b.beforeQuery(query, args)
+
+	var row *sql.Row
+	if b.Cache != nil {
+		if entry, err := b.Cache.acquire(ctx, query); err == nil {
+			row = entry.stmt.QueryRowContext(ctx, args...)
+			b.Cache.release(entry)
+		}
+	}
+	if row == nil {
+		row = b.DB.QueryRowContext(ctx, query, args...)
+	}
+
+	b.afterQuery(ctx, query, args, start, -1, wrapError(row.Err()))
+	return row
+}
+
+func (b *Builder) txExec(tx *sql.Tx, query string, args ...any) (sql.Result, error) {
+	ctx, start := b.beforeQuery(query, args)
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	err = wrapError(err)
+
+	affected := int64(-1)
+	if err == nil {
+		affected, _ = result.RowsAffected()
+	}
+	b.afterQuery(ctx, query, args, start, affected, err)
+	return result, err
+}
+
+func (b *Builder) txQuery(tx *sql.Tx, query string, args ...any) (*sql.Rows, error) {
+	ctx, start := b.beforeQuery(query, args)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	err = wrapError(err)
+	b.afterQuery(ctx, query, args, start, -1, err)
+	return rows, err
+}
+
+func (b *Builder) context() context.Context {
+	if b.WithContext != nil {
+		return b.WithContext
+	}
+	return context.Background()
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+const chunkRowID = "_chunk_rowid"
+
+// * walks the table in ROWID order, see ChunkByID
+func Chunk[T any](b *Builder, size int, fn func(batch []T) error) error {
+	if b.TableName == nil {
+		defer builderClear(b)
+		return fmt.Errorf("table name is required")
+	}
+
+	key := fmt.Sprintf("%s.ROWID", quote(*b.TableName))
+	b.ExprList = append(b.ExprList, fmt.Sprintf("%s AS %s", key, quote(chunkRowID)))
+	return chunk(b, key, chunkRowID, size, fn)
+}
+
+// * seeks past the last key instead of OFFSET, so the callback may update or delete rows
+// * column must be unique, NOT NULL and selected, a non-nil error from fn stops the walk
+func ChunkByID[T any](b *Builder, column string, size int, fn func(batch []T) error) error {
+	if err := ValidateColumn(column); err != nil {
+		defer builderClear(b)
+		return err
+	}
+	if b.TableName == nil {
+		defer builderClear(b)
+		return fmt.Errorf("table name is required")
+	}
+	return chunk(b, fmt.Sprintf("%s.%s", quote(*b.TableName), quote(column)), column, size, fn)
+}
+
+func chunk[T any](b *Builder, key, column string, size int, fn func([]T) error) error {
+	defer builderClear(b)
+
+	if len(b.Error) > 0 {
+		return b.Error[0]
+	}
+
+	if size < 1 {
+		return fmt.Errorf("Chunk: size must be positive")
+	}
+
+	if len(b.OrderByList) > 0 || b.WithLimit != nil || b.WithOffset != nil || b.WithTotal {
+		return fmt.Errorf("Chunk: cannot combine with OrderBy / Limit / Offset / Total")
+	}
+
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("Chunk: type must be struct")
+	}
+
+	var last any
+	for {
+		next := b.Clone()
+		if last != nil {
+			next.groupWhere()
+			next.Where(fmt.Sprintf("%s > ?", key), last)
+		}
+		next.OrderByList = append(next.OrderByList, key+" ASC")
+		next.Limit(size)
+
+		batch, lastKey, err := chunkBatch[T](next, typ, column, size)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < size {
+			return nil
+		}
+		last = lastKey
+	}
+}
+
+// * rows are closed before the callback runs so it can write on the same connection
+func chunkBatch[T any](b *Builder, typ reflect.Type, column string, size int) ([]T, any, error) {
+	rows, err := get(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	position := slices.Index(cols, column)
+	if position < 0 {
+		return nil, nil, fmt.Errorf("Chunk: key column %s must be selected", column)
+	}
+
+	batch := make([]T, 0, size)
+	var last any
+	for rows.Next() {
+		var item T
+		dest := scanTarget(reflect.ValueOf(&item).Elem(), typ, cols)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+		last = reflect.ValueOf(dest[position]).Elem().Interface()
+		if cursorValue(last) == nil {
+			return nil, nil, fmt.Errorf("Chunk: key column %s must be NOT NULL", column)
+		}
+		batch = append(batch, item)
+	}
+	return batch, last, rows.Err()
+}
@@ -3,27 +3,84 @@ package core
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
+func NewConnector(read, write *sql.DB, c Config) *Connector {
+	schema := &atomic.Uint64{}
+	tables := newTableRegistry()
+
+	readBuilder := NewBuilder(read)
+	writeBuilder := NewBuilder(write)
+	readBuilder.Schema = schema
+	writeBuilder.Schema = schema
+	readBuilder.Tables = tables
+	writeBuilder.Tables = tables
+	writeBuilder.Retry = newRetrier(c.Retry)
+	writeBuilder.Queue = newWriteQueue(writeBuilder.session(), c.GroupCommit)
+
+	if c.CacheSize > 0 {
+		readBuilder.Cache = newStmtCache(read, c.CacheSize, schema)
+		writeBuilder.Cache = newStmtCache(write, c.CacheSize, schema)
+	}
+
+	return &Connector{
+		Read:  readBuilder,
+		Write: writeBuilder,
+	}
+}
+
 func (d *Connector) Query(key, query string, args ...any) (*sql.Rows, error) {
-	return d.Read.DB.Query(query, args...)
+	return d.QueryContext(context.Background(), key, query, args...)
 }
 
 func (d *Connector) QueryContext(ctx context.Context, key, query string, args ...any) (*sql.Rows, error) {
-	return d.Read.DB.QueryContext(ctx, query, args...)
+	return d.Read.session().Context(ctx).queryAutoAsignContext(query, args...)
 }
 
 func (d *Connector) Exec(key, query string, args ...any) (sql.Result, error) {
-	return d.Write.DB.Exec(query, args...)
+	return d.ExecContext(context.Background(), key, query, args...)
 }
 
 func (d *Connector) ExecContext(ctx context.Context, key, query string, args ...any) (sql.Result, error) {
-	return d.Write.DB.ExecContext(ctx, query, args...)
+	return d.Write.session().Context(ctx).ExecAutoAsignContext(query, args...)
+}
+
+// * runs through the write builder so hooks observe checkpoint duration
+func (d *Connector) Checkpoint(ctx context.Context, mode string) error {
+	mode = strings.ToUpper(mode)
+	switch mode {
+	case "PASSIVE", "FULL", "RESTART", "TRUNCATE":
+	default:
+		return fmt.Errorf("invalid checkpoint mode: %s", mode)
+	}
+
+	result := &CheckpointResult{
+		Mode: mode,
+		Time: time.Now(),
+	}
+
+	var busy int
+	err := wrapError(d.Write.session().Context(ctx).
+		queryRowAutoAsignContext(fmt.Sprintf("PRAGMA wal_checkpoint(%s)", mode)).
+		Scan(&busy, &result.Log, &result.Checkpointed))
+	result.Busy = busy != 0
+	result.Duration = time.Since(result.Time)
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	d.checkpoint.Store(result)
+	return err
 }
 
 func (d *Connector) Close() {
 	if d.Read != nil && d.Read.DB != nil {
+		d.Read.ClearCache()
 		if err := d.Read.DB.Close(); err != nil {
 			slog.Error("failed to close read db",
 				slog.Any("error", err))
@@ -31,6 +88,8 @@ func (d *Connector) Close() {
 	}
 
 	if d.Write != nil && d.Write.DB != nil {
+		d.Write.Queue.close()
+		d.Write.ClearCache()
 		if err := d.Write.DB.Close(); err != nil {
 			slog.Error("failed to close write db",
 				slog.Any("error", err))
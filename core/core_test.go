@@ -1,9 +1,17 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -1396,3 +1404,2714 @@ func TestEmptyValuesErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestEscapeFTS(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"sqlite", `"sqlite"`},
+		{"go  sqlite", `"go" "sqlite"`},
+		{`say "hi"`, `"say" """hi"""`},
+		{"a OR b*", `"a" "OR" "b*"`},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		if result := EscapeFTS(tt.input); result != tt.expected {
+			t.Errorf("EscapeFTS(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestBuilderMatchErrors(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	t.Run("Match without table", func(t *testing.T) {
+		builder := NewBuilder(db).Match("x")
+		if len(builder.Error) == 0 {
+			t.Error("expected error for missing table")
+		}
+	})
+
+	t.Run("Match with empty escaped query", func(t *testing.T) {
+		builder := NewBuilder(db).Table("docs").Match("  ", true)
+		if len(builder.Error) == 0 {
+			t.Error("expected error for empty query")
+		}
+	})
+
+	t.Run("Highlight with invalid alias", func(t *testing.T) {
+		builder := NewBuilder(db).Table("docs").Highlight(0, "<", ">", "bad-alias")
+		if len(builder.Error) == 0 {
+			t.Error("expected error for invalid alias")
+		}
+	})
+}
+
+func TestBuilderReturning(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	NewBuilder(db).Table("ret_test").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "name", Type: "TEXT"},
+		Column{Name: "score", Type: "INTEGER", Default: 7},
+	)
+
+	type row struct {
+		ID    int64  `db:"id"`
+		Name  string `db:"name"`
+		Score int    `db:"score"`
+	}
+
+	t.Run("Insert returning defaults", func(t *testing.T) {
+		var r row
+		n, err := NewBuilder(db).Table("ret_test").
+			Returning("id", "name", "score").
+			Bind(&r).
+			Insert(map[string]any{"name": "a"})
+		if err != nil {
+			t.Fatalf("insert returning failed: %v", err)
+		}
+		if n != 1 || r.ID < 1 || r.Score != 7 {
+			t.Errorf("unexpected result n=%d row=%+v", n, r)
+		}
+	})
+
+	t.Run("InsertBatch returning into slice", func(t *testing.T) {
+		var rows []row
+		n, err := NewBuilder(db).Table("ret_test").
+			Returning("*").
+			Bind(&rows).
+			InsertBatch([]map[string]any{{"name": "b"}, {"name": "c"}})
+		if err != nil {
+			t.Fatalf("batch returning failed: %v", err)
+		}
+		if n != 2 || len(rows) != 2 {
+			t.Errorf("expected 2 rows, got n=%d len=%d", n, len(rows))
+		}
+	})
+
+	t.Run("Update returning new values", func(t *testing.T) {
+		var rows []row
+		n, err := NewBuilder(db).Table("ret_test").
+			WhereIn("name", []any{"b", "c"}).
+			Increase("score", 3).
+			Returning("id", "score").
+			Bind(&rows).
+			Update()
+		if err != nil {
+			t.Fatalf("update returning failed: %v", err)
+		}
+		if n != 2 {
+			t.Fatalf("expected 2 rows, got %d", n)
+		}
+		for _, r := range rows {
+			if r.Score != 10 {
+				t.Errorf("expected score 10, got %d", r.Score)
+			}
+		}
+	})
+
+	t.Run("Delete returning without bind", func(t *testing.T) {
+		n, err := NewBuilder(db).Table("ret_test").
+			WhereEq("name", "a").
+			Returning("id").
+			Delete()
+		if err != nil {
+			t.Fatalf("delete returning failed: %v", err)
+		}
+		if n != 1 {
+			t.Errorf("expected 1 row, got %d", n)
+		}
+	})
+
+	t.Run("Returning with invalid column", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("ret_test").
+			Returning("invalid-col").
+			Insert(map[string]any{"name": "x"})
+		if err == nil {
+			t.Error("expected error for invalid column")
+		}
+	})
+
+	t.Run("Returning with non-pointer bind", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("ret_test").
+			Returning("id").
+			Bind(row{}).
+			Insert(map[string]any{"name": "x"})
+		if err == nil {
+			t.Error("expected error for non-pointer target")
+		}
+	})
+}
+
+func TestBuilderUpsert(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	NewBuilder(db).Table("counter").Create(
+		Column{Name: "name", Type: "TEXT", IsPrimary: true},
+		Column{Name: "count", Type: "INTEGER"},
+		Column{Name: "locked", Type: "INTEGER", Default: 0},
+	)
+
+	NewBuilder(db).Table("counter").Insert(map[string]any{"name": "a", "count": 1})
+
+	readCount := func(key string) int {
+		var n int
+		db.QueryRow(`SELECT "count" FROM "counter" WHERE "name" = ?`, key).Scan(&n)
+		return n
+	}
+
+	t.Run("DoUpdateSet with excluded reference", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("counter").
+			OnConflict("name").
+			DoUpdateSet(`"count" = "count" + excluded."count"`).
+			Insert(map[string]any{"name": "a", "count": 5})
+		if err != nil {
+			t.Fatalf("upsert failed: %v", err)
+		}
+		if n := readCount("a"); n != 6 {
+			t.Errorf("expected 6, got %d", n)
+		}
+	})
+
+	t.Run("DoUpdate with Excluded value", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("counter").
+			OnConflict("name").
+			DoUpdate(map[string]any{"count": Excluded("count")}).
+			Insert(map[string]any{"name": "a", "count": 2})
+		if err != nil {
+			t.Fatalf("upsert failed: %v", err)
+		}
+		if n := readCount("a"); n != 2 {
+			t.Errorf("expected 2, got %d", n)
+		}
+	})
+
+	t.Run("DoUpdateWhere skips locked rows", func(t *testing.T) {
+		db.Exec(`UPDATE "counter" SET "locked" = 1 WHERE "name" = 'a'`)
+		_, err := NewBuilder(db).Table("counter").
+			OnConflict("name").
+			DoUpdateExcluded("count").
+			DoUpdateWhere(`"locked" = ?`, 0).
+			Insert(map[string]any{"name": "a", "count": 99})
+		if err != nil {
+			t.Fatalf("upsert failed: %v", err)
+		}
+		if n := readCount("a"); n != 2 {
+			t.Errorf("expected 2, got %d", n)
+		}
+	})
+
+	t.Run("DoNothing", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("counter").
+			OnConflict("name").
+			DoNothing().
+			Insert(map[string]any{"name": "a", "count": 50})
+		if err != nil {
+			t.Fatalf("upsert failed: %v", err)
+		}
+		if n := readCount("a"); n != 2 {
+			t.Errorf("expected 2, got %d", n)
+		}
+	})
+
+	t.Run("InsertBatch with upsert", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("counter").
+			OnConflict("name").
+			DoUpdateSet(`"count" = "count" + excluded."count"`).
+			InsertBatch([]map[string]any{
+				{"name": "b", "count": 1},
+				{"name": "c", "count": 1},
+			})
+		if err != nil {
+			t.Fatalf("batch upsert failed: %v", err)
+		}
+		_, err = NewBuilder(db).Table("counter").
+			OnConflict("name").
+			DoUpdateSet(`"count" = "count" + excluded."count"`).
+			InsertBatch([]map[string]any{
+				{"name": "b", "count": 4},
+				{"name": "c", "count": 9},
+			})
+		if err != nil {
+			t.Fatalf("batch upsert failed: %v", err)
+		}
+		if n := readCount("b"); n != 5 {
+			t.Errorf("expected 5, got %d", n)
+		}
+		if n := readCount("c"); n != 10 {
+			t.Errorf("expected 10, got %d", n)
+		}
+	})
+
+	t.Run("InsertBatch with conflict mode", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("counter").
+			Conflict(Ignore).
+			InsertBatch([]map[string]any{
+				{"name": "b", "count": 100},
+				{"name": "d", "count": 1},
+			})
+		if err != nil {
+			t.Fatalf("batch insert or ignore failed: %v", err)
+		}
+		if n := readCount("b"); n != 5 {
+			t.Errorf("expected 5, got %d", n)
+		}
+	})
+
+	t.Run("DoNothing combined with DoUpdate", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("counter").
+			OnConflict("name").
+			DoNothing().
+			DoUpdateExcluded("count").
+			Insert(map[string]any{"name": "a", "count": 1})
+		if err == nil {
+			t.Error("expected error for conflicting actions")
+		}
+	})
+
+	t.Run("OnConflict without action", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("counter").
+			OnConflict("name").
+			Insert(map[string]any{"name": "a", "count": 1})
+		if err == nil {
+			t.Error("expected error for missing action")
+		}
+	})
+
+	t.Run("OnConflict with invalid column", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("counter").
+			OnConflict("invalid-col").
+			DoNothing().
+			Insert(map[string]any{"name": "a", "count": 1})
+		if err == nil {
+			t.Error("expected error for invalid column")
+		}
+	})
+}
+
+func TestBuilderInsertBatchChunk(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	NewBuilder(db).Table("chunk_test").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true},
+		Column{Name: "name", Type: "TEXT"},
+		Column{Name: "value", Type: "INTEGER"},
+	)
+
+	t.Run("InsertBatch beyond variable limit", func(t *testing.T) {
+		data := make([]map[string]any, 20000)
+		for i := range data {
+			data[i] = map[string]any{"id": i + 1, "name": "row", "value": i}
+		}
+
+		affected, err := NewBuilder(db).Table("chunk_test").InsertBatch(data)
+		if err != nil {
+			t.Fatalf("chunked insert failed: %v", err)
+		}
+		if affected != 20000 {
+			t.Errorf("expected 20000 affected rows, got %d", affected)
+		}
+	})
+
+	t.Run("InsertBatch chunks roll back together", func(t *testing.T) {
+		saved := maxVariables
+		maxVariables = 6
+		defer func() { maxVariables = saved }()
+
+		before, _ := NewBuilder(db).Table("chunk_test").Count()
+		_, err := NewBuilder(db).Table("chunk_test").InsertBatch([]map[string]any{
+			{"id": 30001, "name": "a", "value": 1},
+			{"id": 30002, "name": "b", "value": 2},
+			{"id": 1, "name": "dup", "value": 3},
+		})
+		if err == nil {
+			t.Fatal("expected error for duplicate id in second chunk")
+		}
+
+		after, _ := NewBuilder(db).Table("chunk_test").Count()
+		if after != before {
+			t.Errorf("expected rollback, count changed from %d to %d", before, after)
+		}
+	})
+
+	t.Run("InsertBatch chunks with returning", func(t *testing.T) {
+		saved := maxVariables
+		maxVariables = 4
+		defer func() { maxVariables = saved }()
+
+		var ids []struct {
+			ID int64 `db:"id"`
+		}
+		affected, err := NewBuilder(db).Table("chunk_test").
+			Returning("id").
+			Bind(&ids).
+			InsertBatch([]map[string]any{
+				{"id": 40001, "name": "a", "value": 0},
+				{"id": 40002, "name": "b", "value": 0},
+				{"id": 40003, "name": "c", "value": 0},
+			})
+		if err != nil {
+			t.Fatalf("chunked returning failed: %v", err)
+		}
+		if affected != 3 || len(ids) != 3 {
+			t.Errorf("expected 3 returned rows, got affected=%d len=%d", affected, len(ids))
+		}
+	})
+
+	t.Run("InsertBatch with mismatched keys", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("chunk_test").InsertBatch([]map[string]any{
+			{"name": "a", "value": 1},
+			{"name": "b"},
+		})
+		if err == nil {
+			t.Error("expected error for missing column")
+		}
+
+		_, err = NewBuilder(db).Table("chunk_test").InsertBatch([]map[string]any{
+			{"name": "a", "value": 1},
+			{"name": "b", "id": 50000},
+		})
+		if err == nil {
+			t.Error("expected error for different column")
+		}
+	})
+}
+
+func TestBuilderLoader(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	NewBuilder(db).Table("load_test").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true},
+		Column{Name: "name", Type: "TEXT"},
+	)
+
+	t.Run("Add commits every batch size", func(t *testing.T) {
+		var batches []LoaderBatch
+		loader, err := NewBuilder(db).Table("load_test").Loader("id", "name")
+		if err != nil {
+			t.Fatalf("loader failed: %v", err)
+		}
+		loader.BatchSize(100).OnBatch(func(batch LoaderBatch) {
+			batches = append(batches, batch)
+		})
+
+		for i := 1; i <= 250; i++ {
+			if err := loader.Add(i, "row"); err != nil {
+				t.Fatalf("add failed: %v", err)
+			}
+		}
+		if err := loader.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+
+		if len(batches) != 3 || batches[0].Rows != 100 || batches[2].Rows != 50 {
+			t.Errorf("unexpected batches: %+v", batches)
+		}
+		count, _ := NewBuilder(db).Table("load_test").Count()
+		if count != 250 {
+			t.Errorf("expected 250, got %d", count)
+		}
+	})
+
+	t.Run("Load from channel with conflict mode", func(t *testing.T) {
+		loader, err := NewBuilder(db).Table("load_test").Conflict(Ignore).Loader("id", "name")
+		if err != nil {
+			t.Fatalf("loader failed: %v", err)
+		}
+		defer loader.Close()
+
+		ch := make(chan []any)
+		go func() {
+			for i := 240; i <= 300; i++ {
+				ch <- []any{i, "chan"}
+			}
+			close(ch)
+		}()
+
+		if err := loader.Load(ch); err != nil {
+			t.Fatalf("load failed: %v", err)
+		}
+		count, _ := NewBuilder(db).Table("load_test").Count()
+		if count != 300 {
+			t.Errorf("expected 300, got %d", count)
+		}
+	})
+
+	t.Run("FlushInterval commits partial batch", func(t *testing.T) {
+		done := make(chan LoaderBatch, 1)
+		loader, err := NewBuilder(db).Table("load_test").Loader("id", "name")
+		if err != nil {
+			t.Fatalf("loader failed: %v", err)
+		}
+		defer loader.Close()
+		loader.FlushInterval(20 * time.Millisecond).OnBatch(func(batch LoaderBatch) {
+			done <- batch
+		})
+
+		loader.Add(1000, "timed")
+		select {
+		case batch := <-done:
+			if batch.Rows != 1 || batch.Error != nil {
+				t.Errorf("unexpected batch: %+v", batch)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected interval flush")
+		}
+	})
+
+	t.Run("Failed row keeps accepted rows", func(t *testing.T) {
+		var batch LoaderBatch
+		loader, err := NewBuilder(db).Table("load_test").Loader("id", "name")
+		if err != nil {
+			t.Fatalf("loader failed: %v", err)
+		}
+		loader.OnBatch(func(b LoaderBatch) { batch = b })
+
+		for id := 2000; id < 2005; id++ {
+			if err := loader.Add(id, "ok"); err != nil {
+				t.Fatalf("add failed: %v", err)
+			}
+		}
+		if err := loader.Add(1, "duplicate"); !errors.Is(err, ErrUniqueViolation) {
+			t.Fatalf("expected ErrUniqueViolation for duplicate id, got %v", err)
+		}
+		if err := loader.Add(2005, "after"); err != nil {
+			t.Fatalf("add after failed row: %v", err)
+		}
+		if err := loader.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+
+		if batch.Error != nil || batch.Rows != 6 || batch.Failed != 1 {
+			t.Errorf("unexpected batch: %+v", batch)
+		}
+		count, _ := NewBuilder(db).Table("load_test").WhereGt("id", 1999).WhereLt("id", 2010).Count()
+		if count != 6 {
+			t.Errorf("expected 6 accepted rows, got %d", count)
+		}
+	})
+
+	t.Run("Load returns failed rows", func(t *testing.T) {
+		var batches []LoaderBatch
+		loader, err := NewBuilder(db).Table("load_test").Loader("id", "name")
+		if err != nil {
+			t.Fatalf("loader failed: %v", err)
+		}
+		defer loader.Close()
+		loader.OnBatch(func(b LoaderBatch) { batches = append(batches, b) })
+
+		ch := make(chan []any)
+		go func() {
+			for _, id := range []int{3001, 3002, 3003, 3003, 3004} {
+				ch <- []any{id, "load"}
+			}
+			close(ch)
+		}()
+
+		err = loader.Load(ch)
+		if !errors.Is(err, ErrUniqueViolation) {
+			t.Fatalf("expected unique violation from Load, got %v", err)
+		}
+		if len(batches) != 1 || batches[0].Rows != 4 || batches[0].Failed != 1 {
+			t.Errorf("unexpected batches: %+v", batches)
+		}
+
+		count, _ := NewBuilder(db).Table("load_test").WhereGt("id", 3000).Count()
+		if count != 4 {
+			t.Errorf("expected every distinct row to be stored, got %d", count)
+		}
+	})
+
+	t.Run("Add with wrong value count", func(t *testing.T) {
+		loader, err := NewBuilder(db).Table("load_test").Loader("id", "name")
+		if err != nil {
+			t.Fatalf("loader failed: %v", err)
+		}
+		defer loader.Close()
+
+		if err := loader.Add(1); err == nil {
+			t.Error("expected error for value count")
+		}
+	})
+
+	t.Run("Loader with invalid column", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("load_test").Loader("invalid-col")
+		if err == nil {
+			t.Error("expected error for invalid column")
+		}
+	})
+}
+
+func TestBuilderStmtCache(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	conn := NewConnector(db, db, Config{CacheSize: 2})
+
+	conn.Write.Table("cache_test").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "name", Type: "TEXT"},
+	)
+
+	t.Run("Repeated query hits cache", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			if _, err := conn.Write.Table("cache_test").Insert(map[string]any{"name": "a"}); err != nil {
+				t.Fatalf("insert failed: %v", err)
+			}
+		}
+
+		stats := conn.Write.CacheStats()
+		if stats.Misses != 1 || stats.Hits != 4 {
+			t.Errorf("expected 1 miss and 4 hits, got %+v", stats)
+		}
+	})
+
+	t.Run("LRU evicts beyond capacity", func(t *testing.T) {
+		conn.Read.Table("cache_test").WhereEq("id", 1).Count()
+		conn.Read.Table("cache_test").WhereEq("name", "a").Count()
+		conn.Read.Table("cache_test").WhereGt("id", 0).Count()
+
+		stats := conn.Read.CacheStats()
+		if stats.Size != 2 || stats.Evictions != 1 {
+			t.Errorf("expected size 2 with 1 eviction, got %+v", stats)
+		}
+	})
+
+	t.Run("Schema change invalidates all caches", func(t *testing.T) {
+		before := conn.Read.CacheStats().Evictions
+		if _, err := conn.Exec("", `ALTER TABLE "cache_test" ADD COLUMN "extra" TEXT`); err != nil {
+			t.Fatalf("alter failed: %v", err)
+		}
+
+		var rows []struct {
+			ID    int64          `db:"id"`
+			Extra sql.NullString `db:"extra"`
+		}
+		if _, err := conn.Read.Table("cache_test").WhereGt("id", 0).Bind(&rows).Get(); err != nil {
+			t.Fatalf("get failed: %v", err)
+		}
+
+		stats := conn.Read.CacheStats()
+		if stats.Evictions != before+2 || stats.Size != 1 {
+			t.Errorf("expected cache flushed after schema change, got %+v", stats)
+		}
+	})
+
+	t.Run("Cache disabled by default", func(t *testing.T) {
+		plain := NewConnector(db, db, Config{})
+		plain.Read.Table("cache_test").Count()
+		if stats := plain.Read.CacheStats(); stats != (CacheStats{}) {
+			t.Errorf("expected empty stats, got %+v", stats)
+		}
+	})
+
+	t.Run("Concurrent use with eviction", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				b := &Builder{DB: db, Cache: conn.Read.Cache}
+				for j := 0; j < 50; j++ {
+					if _, err := b.Table("cache_test").WhereGt("id", j%5).Limit(i + 1).Count(); err != nil {
+						t.Errorf("count failed: %v", err)
+						return
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+func TestBuilderConcurrentTable(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	conn := NewConnector(db, db, Config{CacheSize: 8})
+	conn.Write.Table("race_test").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "worker", Type: "INTEGER"},
+	)
+
+	t.Run("Table returns independent builder", func(t *testing.T) {
+		root := NewBuilder(db).WhereEq("worker", 1)
+		a := root.Table("race_test").WhereEq("id", 1)
+		b := root.Table("race_test")
+
+		if root.TableName != nil {
+			t.Error("expected root builder to stay untouched")
+		}
+		if len(a.WhereList) != 2 || len(b.WhereList) != 1 || len(root.WhereList) != 1 {
+			t.Errorf("expected independent where lists, got %d %d %d",
+				len(a.WhereList), len(b.WhereList), len(root.WhereList))
+		}
+	})
+
+	t.Run("Shared connector from many goroutines", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for w := 0; w < 16; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 20; i++ {
+					if _, err := conn.Write.Table("race_test").Insert(map[string]any{"worker": w}); err != nil {
+						t.Errorf("insert failed: %v", err)
+						return
+					}
+
+					var rows []struct {
+						ID     int64 `db:"id"`
+						Worker int   `db:"worker"`
+					}
+					if _, err := conn.Read.Table("race_test").WhereEq("worker", w).Bind(&rows).Get(); err != nil {
+						t.Errorf("get failed: %v", err)
+						return
+					}
+					for _, r := range rows {
+						if r.Worker != w {
+							t.Errorf("worker %d read row of worker %d", w, r.Worker)
+							return
+						}
+					}
+					if len(rows) != i+1 {
+						t.Errorf("worker %d expected %d rows, got %d", w, i+1, len(rows))
+						return
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		count, _ := conn.Read.Table("race_test").Count()
+		if count != 16*20 {
+			t.Errorf("expected %d rows, got %d", 16*20, count)
+		}
+	})
+}
+
+func TestBuilderClone(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	NewBuilder(db).Table("clone_test").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "tenant", Type: "INTEGER"},
+		Column{Name: "active", Type: "INTEGER"},
+		Column{Name: "name", Type: "TEXT"},
+	)
+
+	NewBuilder(db).Table("clone_test").InsertBatch([]map[string]any{
+		{"tenant": 1, "active": 1, "name": "a"},
+		{"tenant": 1, "active": 1, "name": "b"},
+		{"tenant": 1, "active": 0, "name": "c"},
+		{"tenant": 2, "active": 1, "name": "d"},
+	})
+
+	t.Run("Clone derives independent variants", func(t *testing.T) {
+		base := NewBuilder(db).Table("clone_test").WhereEq("tenant", 1).WhereEq("active", 1)
+
+		named, err := base.Clone().WhereEq("name", "a").Count()
+		if err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		all, err := base.Clone().Count()
+		if err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		if named != 1 || all != 2 {
+			t.Errorf("expected 1 and 2, got %d and %d", named, all)
+		}
+		if len(base.WhereList) != 2 {
+			t.Errorf("expected base to keep 2 conditions, got %d", len(base.WhereList))
+		}
+	})
+
+	t.Run("ToSQL does not execute or clear", func(t *testing.T) {
+		b := NewBuilder(db).Table("clone_test").WhereEq("tenant", 1).OrderBy("id", Desc).Limit(2)
+		query, args, err := b.ToSQL()
+		if err != nil {
+			t.Fatalf("to sql failed: %v", err)
+		}
+		expected := `SELECT * FROM "clone_test" WHERE "tenant" = ? ORDER BY "id" DESC LIMIT 2`
+		if query != expected {
+			t.Errorf("expected %q, got %q", expected, query)
+		}
+		if len(args) != 1 || args[0] != 1 {
+			t.Errorf("unexpected args %v", args)
+		}
+		if len(b.WhereList) != 1 {
+			t.Error("expected builder state to remain")
+		}
+	})
+
+	t.Run("Compiled query runs with different args", func(t *testing.T) {
+		q, err := NewBuilder(db).Table("clone_test").WhereEq("tenant", 1).WhereEq("active", 1).Compile()
+		if err != nil {
+			t.Fatalf("compile failed: %v", err)
+		}
+
+		var rows []struct {
+			Name string `db:"name"`
+		}
+		if err := q.Bind(&rows); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if len(rows) != 2 {
+			t.Errorf("expected 2 rows, got %d", len(rows))
+		}
+
+		if err := q.Bind(&rows, 2, 1); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if len(rows) != 1 || rows[0].Name != "d" {
+			t.Errorf("expected tenant 2 row, got %+v", rows)
+		}
+
+		if _, err := q.Get(1); err == nil {
+			t.Error("expected error for wrong arg count")
+		}
+	})
+
+	t.Run("Compile propagates builder error", func(t *testing.T) {
+		_, err := NewBuilder(db).Table("clone_test").WhereEq("invalid-col", 1).Compile()
+		if err == nil {
+			t.Error("expected error to propagate")
+		}
+	})
+}
+
+func TestBuilderStatementSQL(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	t.Run("InsertSQL with upsert and returning", func(t *testing.T) {
+		query, args, err := NewBuilder(db).Table("users").
+			OnConflict("email").
+			DoUpdateExcluded("name").
+			Returning("id").
+			InsertSQL(map[string]any{"name": "a", "email": "a@x"})
+		if err != nil {
+			t.Fatalf("insert sql failed: %v", err)
+		}
+		expected := `INSERT INTO "users" ("email", "name") VALUES (?, ?) ON CONFLICT ("email") DO UPDATE SET "name" = excluded."name" RETURNING "id"`
+		if query != expected {
+			t.Errorf("expected %q, got %q", expected, query)
+		}
+		if len(args) != 2 {
+			t.Errorf("expected 2 args, got %v", args)
+		}
+	})
+
+	t.Run("InsertBatchSQL", func(t *testing.T) {
+		query, args, err := NewBuilder(db).Table("users").
+			InsertBatchSQL([]map[string]any{{"name": "a"}, {"name": "b"}})
+		if err != nil {
+			t.Fatalf("insert batch sql failed: %v", err)
+		}
+		expected := `INSERT INTO "users" ("name") VALUES (?), (?)`
+		if query != expected || len(args) != 2 {
+			t.Errorf("expected %q, got %q %v", expected, query, args)
+		}
+	})
+
+	t.Run("UpdateSQL", func(t *testing.T) {
+		query, args, err := NewBuilder(db).Table("users").
+			WhereEq("id", 1).
+			Increase("visits").
+			UpdateSQL(map[string]any{"name": "b"})
+		if err != nil {
+			t.Fatalf("update sql failed: %v", err)
+		}
+		expected := `UPDATE "users" SET "visits" = "visits" + 1, "name" = ? WHERE "id" = ?`
+		if query != expected || len(args) != 2 {
+			t.Errorf("expected %q, got %q %v", expected, query, args)
+		}
+	})
+
+	t.Run("DeleteSQL and CountSQL", func(t *testing.T) {
+		query, _, err := NewBuilder(db).Table("users").WhereEq("id", 1).DeleteSQL()
+		if err != nil || query != `DELETE FROM "users" WHERE "id" = ?` {
+			t.Errorf("unexpected delete sql %q: %v", query, err)
+		}
+
+		query, _, err = NewBuilder(db).Table("users").WhereEq("id", 1).CountSQL()
+		if err != nil || query != `SELECT COUNT(*) FROM "users" WHERE "id" = ?` {
+			t.Errorf("unexpected count sql %q: %v", query, err)
+		}
+
+		if _, _, err := NewBuilder(db).Table("users").DeleteSQL(); err == nil {
+			t.Error("expected error for delete without where")
+		}
+	})
+
+	t.Run("Interpolated escapes values", func(t *testing.T) {
+		query, err := NewBuilder(db).Table("users").
+			WhereEq("name", "O'Brien").
+			WhereIn("id", []any{1, nil, true, []byte{0xab}}).
+			Interpolated()
+		if err != nil {
+			t.Fatalf("interpolated failed: %v", err)
+		}
+		expected := `SELECT * FROM "users" WHERE "name" = 'O''Brien' AND "id" IN (1, NULL, 1, X'ab')`
+		if query != expected {
+			t.Errorf("expected %q, got %q", expected, query)
+		}
+	})
+}
+
+func TestInterpolate(t *testing.T) {
+	tests := []struct {
+		query    string
+		args     []any
+		expected string
+	}{
+		{"SELECT ?", []any{"a'b"}, "SELECT 'a''b'"},
+		{"SELECT '?', ?", []any{1}, "SELECT '?', 1"},
+		{`SELECT "col?" FROM t WHERE x = ?`, []any{2.5}, `SELECT "col?" FROM t WHERE x = 2.5`},
+		{"SELECT ?, ?", []any{sql.NullString{}, time.Unix(0, 0).UTC()}, "SELECT NULL, '1970-01-01T00:00:00Z'"},
+		{"SELECT ?", nil, "SELECT ?"},
+	}
+
+	for _, tt := range tests {
+		if result := Interpolate(tt.query, tt.args); result != tt.expected {
+			t.Errorf("Interpolate(%q) = %q, expected %q", tt.query, result, tt.expected)
+		}
+	}
+}
+
+func TestBuilderExplain(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	NewBuilder(db).Table("plan_test").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "email", Type: "TEXT"},
+		Column{Name: "name", Type: "TEXT"},
+	)
+	db.Exec(`CREATE INDEX "idx_plan_email" ON "plan_test" ("email")`)
+
+	t.Run("Index search", func(t *testing.T) {
+		plan, err := NewBuilder(db).Table("plan_test").WhereEq("email", "a@x").Explain()
+		if err != nil {
+			t.Fatalf("explain failed: %v", err)
+		}
+		if !plan.UsesIndex("idx_plan_email") {
+			t.Errorf("expected index use, got:\n%s", plan)
+		}
+		if len(plan.FullScans()) != 0 {
+			t.Errorf("expected no full scan, got:\n%s", plan)
+		}
+	})
+
+	t.Run("Primary key search", func(t *testing.T) {
+		plan, err := NewBuilder(db).Table("plan_test").WhereEq("id", 1).Explain()
+		if err != nil {
+			t.Fatalf("explain failed: %v", err)
+		}
+		if !plan.UsesIndex("PRIMARY KEY") {
+			t.Errorf("expected primary key lookup, got:\n%s", plan)
+		}
+	})
+
+	t.Run("Full scan", func(t *testing.T) {
+		plan, err := NewBuilder(db).Table("plan_test").WhereEq("name", "a").Explain()
+		if err != nil {
+			t.Fatalf("explain failed: %v", err)
+		}
+		scans := plan.FullScans()
+		if len(scans) != 1 || scans[0].Table != "plan_test" {
+			t.Errorf("expected full scan of plan_test, got:\n%s", plan)
+		}
+	})
+}
+
+type recordHook struct {
+	mu     sync.Mutex
+	before []string
+	after  []string
+	rows   []int64
+	errs   []error
+}
+
+type hookKey struct{}
+
+func (h *recordHook) BeforeQuery(ctx context.Context, query string, args []any) context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.before = append(h.before, query)
+	return context.WithValue(ctx, hookKey{}, query)
+}
+
+func (h *recordHook) AfterQuery(ctx context.Context, query string, args []any, duration time.Duration, rowsAffected int64, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ctx.Value(hookKey{}) != query {
+		h.errs = append(h.errs, fmt.Errorf("context from BeforeQuery not passed for %s", query))
+	}
+	h.after = append(h.after, query)
+	h.rows = append(h.rows, rowsAffected)
+	if err != nil {
+		h.errs = append(h.errs, err)
+	}
+}
+
+func TestBuilderHooks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	hook := &recordHook{}
+	conn := NewConnector(db, db, Config{})
+	conn.Use(hook)
+
+	conn.Write.Table("hook_test").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "name", Type: "TEXT"},
+	)
+
+	t.Run("Every execution path calls hooks", func(t *testing.T) {
+		conn.Write.Table("hook_test").Insert(map[string]any{"name": "a"})
+		conn.Write.Table("hook_test").InsertBatch([]map[string]any{{"name": "b"}, {"name": "c"}})
+		conn.Write.Table("hook_test").WhereEq("name", "a").Update(map[string]any{"name": "z"})
+		conn.Read.Table("hook_test").Count()
+		conn.Read.Table("hook_test").First()
+		rows, _ := conn.Read.Table("hook_test").Get()
+		rows.Close()
+		rows, _ = conn.Query("", `SELECT 1`)
+		rows.Close()
+		conn.Exec("", `UPDATE "hook_test" SET "name" = 'y' WHERE "id" = 2`)
+		conn.Write.Table("hook_test").WhereEq("id", 3).Delete()
+
+		if len(hook.before) != 10 || len(hook.after) != 10 {
+			t.Fatalf("expected 10 before and after calls, got %d and %d", len(hook.before), len(hook.after))
+		}
+		if hook.rows[1] != 1 || hook.rows[2] != 2 || hook.rows[3] != 1 {
+			t.Errorf("unexpected rows affected: %v", hook.rows)
+		}
+		if len(hook.errs) != 0 {
+			t.Errorf("unexpected errors: %v", hook.errs)
+		}
+	})
+
+	t.Run("Errors reach AfterQuery", func(t *testing.T) {
+		conn.Write.Table("missing_table").Insert(map[string]any{"name": "a"})
+		if len(hook.errs) != 1 {
+			t.Errorf("expected 1 error, got %v", hook.errs)
+		}
+	})
+}
+
+func TestLogHook(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	builder := NewBuilder(db).Use(NewLogHook(logger, 0, "password"))
+	builder.Table("log_test").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "email", Type: "TEXT"},
+		Column{Name: "password", Type: "TEXT"},
+	)
+
+	t.Run("Redacts configured columns", func(t *testing.T) {
+		buf.Reset()
+		builder.Table("log_test").Insert(map[string]any{"email": "a@x", "password": "hunter2"})
+		builder.Table("log_test").WhereEq("password", "hunter3").Count()
+
+		if strings.Contains(buf.String(), "hunter") {
+			t.Errorf("expected password to be redacted, got %s", buf.String())
+		}
+		if !strings.Contains(buf.String(), "a@x") || !strings.Contains(buf.String(), "[REDACTED]") {
+			t.Errorf("expected email and redaction marker, got %s", buf.String())
+		}
+	})
+
+	t.Run("Slow query logs warning", func(t *testing.T) {
+		buf.Reset()
+		slow := NewBuilder(db).Use(NewLogHook(logger, time.Nanosecond))
+		slow.Table("log_test").Count()
+		if !strings.Contains(buf.String(), `"level":"WARN"`) || !strings.Contains(buf.String(), "slow query") {
+			t.Errorf("expected slow query warning, got %s", buf.String())
+		}
+	})
+
+	t.Run("Failure logs error", func(t *testing.T) {
+		buf.Reset()
+		builder.Table("missing_table").Count()
+		if !strings.Contains(buf.String(), `"level":"ERROR"`) {
+			t.Errorf("expected error log, got %s", buf.String())
+		}
+	})
+}
+
+func TestPlaceholderColumns(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{`INSERT INTO "t" ("a", "b") VALUES (?, ?), (?, ?)`, []string{"a", "b", "a", "b"}},
+		{`INSERT INTO "t" ("a") VALUES (?) ON CONFLICT ("a") DO UPDATE SET "b" = ?`, []string{"a", "b"}},
+		{`UPDATE "t" SET "a" = ?, "b" = ? WHERE "id" IN (?, ?)`, []string{"a", "b", "id", "id"}},
+		{`SELECT * FROM "t" WHERE name = ? AND "c" BETWEEN ? AND ?`, []string{"name", "c", "c"}},
+		{`SELECT * FROM "t" WHERE "a" = '?' OR "b" = ?`, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		result := placeholderColumns(tt.query)
+		if !slices.Equal(result, tt.expected) {
+			t.Errorf("placeholderColumns(%q) = %v, expected %v", tt.query, result, tt.expected)
+		}
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "errors.db")
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?_foreign_keys=1")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	builder := NewBuilder(db)
+	builder.Table("teams").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true},
+	)
+	builder.Table("members").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "email", Type: "TEXT", IsUnique: true},
+		Column{Name: "team_id", Type: "INTEGER", IsNullable: true, ForeignKey: &Foreign{Table: "teams", Column: "id"}},
+	)
+	if _, err := db.Exec(`CREATE TABLE scores (value INTEGER CHECK (value >= 0))`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	builder.Table("teams").Insert(map[string]any{"id": 1})
+	builder.Table("members").Insert(map[string]any{"email": "a@example.com", "team_id": 1})
+
+	t.Run("Unique violation with column", func(t *testing.T) {
+		_, err := builder.Table("members").Insert(map[string]any{"email": "a@example.com", "team_id": 1})
+		if !errors.Is(err, ErrUniqueViolation) {
+			t.Fatalf("expected ErrUniqueViolation, got %v", err)
+		}
+
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Fatalf("expected *Error, got %T", err)
+		}
+		if e.Table != "members" || !slices.Equal(e.Columns, []string{"email"}) {
+			t.Errorf("expected members.email, got %s %v", e.Table, e.Columns)
+		}
+	})
+
+	t.Run("Foreign key violation", func(t *testing.T) {
+		_, err := builder.Table("members").Insert(map[string]any{"email": "b@example.com", "team_id": 99})
+		if !errors.Is(err, ErrForeignKey) {
+			t.Errorf("expected ErrForeignKey, got %v", err)
+		}
+	})
+
+	t.Run("Not null violation", func(t *testing.T) {
+		_, err := builder.Table("members").Insert(map[string]any{"email": nil})
+		if !errors.Is(err, ErrNotNull) {
+			t.Fatalf("expected ErrNotNull, got %v", err)
+		}
+
+		var e *Error
+		if errors.As(err, &e) && !slices.Equal(e.Columns, []string{"email"}) {
+			t.Errorf("expected email column, got %v", e.Columns)
+		}
+	})
+
+	t.Run("Check violation", func(t *testing.T) {
+		_, err := builder.Table("scores").Insert(map[string]any{"value": -1})
+		if !errors.Is(err, ErrCheck) {
+			t.Errorf("expected ErrCheck, got %v", err)
+		}
+	})
+
+	t.Run("Read only", func(t *testing.T) {
+		ro, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro")
+		if err != nil {
+			t.Fatalf("failed to open db: %v", err)
+		}
+		defer ro.Close()
+
+		_, err = NewBuilder(ro).Table("teams").Insert(map[string]any{"id": 2})
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("expected ErrReadOnly, got %v", err)
+		}
+	})
+
+	t.Run("Busy", func(t *testing.T) {
+		lock, err := db.Begin()
+		if err != nil {
+			t.Fatalf("failed to begin: %v", err)
+		}
+		defer lock.Rollback()
+		if _, err := lock.Exec("INSERT INTO teams (id) VALUES (3)"); err != nil {
+			t.Fatalf("failed to lock: %v", err)
+		}
+
+		other, err := sql.Open("sqlite3", "file:"+dbPath+"?_busy_timeout=0")
+		if err != nil {
+			t.Fatalf("failed to open db: %v", err)
+		}
+		defer other.Close()
+
+		_, err = NewBuilder(other).Table("teams").Insert(map[string]any{"id": 4})
+		if !errors.Is(err, ErrBusy) {
+			t.Errorf("expected ErrBusy, got %v", err)
+		}
+	})
+
+	t.Run("Other errors pass through", func(t *testing.T) {
+		_, err := builder.Table("missing").Count()
+		var e *Error
+		if err == nil || errors.As(err, &e) {
+			t.Errorf("expected unclassified error, got %v", err)
+		}
+	})
+}
+
+func TestRetry(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "retry.db")
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	NewBuilder(db).Table("jobs").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "name", Type: "TEXT"},
+	)
+
+	open := func(t *testing.T, policy RetryPolicy) *Connector {
+		t.Helper()
+		other, err := sql.Open("sqlite3", "file:"+dbPath+"?_busy_timeout=0&_txlock=immediate")
+		if err != nil {
+			t.Fatalf("failed to open db: %v", err)
+		}
+		t.Cleanup(func() { other.Close() })
+		return NewConnector(other, other, Config{Retry: policy})
+	}
+
+	lock := func(t *testing.T) *sql.Tx {
+		t.Helper()
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("failed to begin: %v", err)
+		}
+		if _, err := tx.Exec("INSERT INTO jobs (name) VALUES ('lock')"); err != nil {
+			t.Fatalf("failed to lock: %v", err)
+		}
+		return tx
+	}
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		conn := open(t, RetryPolicy{})
+		tx := lock(t)
+		defer tx.Rollback()
+
+		_, err := conn.Write.Table("jobs").Insert(map[string]any{"name": "a"})
+		if !errors.Is(err, ErrBusy) {
+			t.Errorf("expected ErrBusy, got %v", err)
+		}
+	})
+
+	t.Run("Retries until the lock is released", func(t *testing.T) {
+		conn := open(t, RetryPolicy{MaxAttempts: 20, BaseDelay: 5 * time.Millisecond, MaxDelay: 20 * time.Millisecond})
+		tx := lock(t)
+		time.AfterFunc(50*time.Millisecond, func() { tx.Rollback() })
+
+		if _, err := conn.Write.Table("jobs").Insert(map[string]any{"name": "a"}); err != nil {
+			t.Fatalf("expected insert to succeed after retry, got %v", err)
+		}
+
+		stats, err := conn.Stats()
+		if err != nil {
+			t.Fatalf("stats failed: %v", err)
+		}
+		if stats.Retries == 0 {
+			t.Error("expected retries to be counted")
+		}
+	})
+
+	t.Run("Transactions retry BEGIN", func(t *testing.T) {
+		conn := open(t, RetryPolicy{MaxAttempts: 20, BaseDelay: 5 * time.Millisecond, MaxDelay: 20 * time.Millisecond})
+		tx := lock(t)
+		time.AfterFunc(50*time.Millisecond, func() { tx.Rollback() })
+
+		restore := maxVariables
+		maxVariables = 2
+		defer func() { maxVariables = restore }()
+
+		affected, err := conn.Write.Table("jobs").InsertBatch([]map[string]any{{"name": "b"}, {"name": "c"}})
+		if err != nil {
+			t.Fatalf("expected batch to succeed after retry, got %v", err)
+		}
+		if affected != 2 {
+			t.Errorf("expected 2 rows, got %d", affected)
+		}
+	})
+
+	t.Run("Gives up after MaxAttempts", func(t *testing.T) {
+		conn := open(t, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+		tx := lock(t)
+		defer tx.Rollback()
+
+		_, err := conn.Write.Table("jobs").Insert(map[string]any{"name": "d"})
+		if !errors.Is(err, ErrBusy) {
+			t.Errorf("expected ErrBusy, got %v", err)
+		}
+		if retries := conn.Write.Retry.count(); retries != 2 {
+			t.Errorf("expected 2 retries, got %d", retries)
+		}
+	})
+
+	t.Run("Context cancels backoff", func(t *testing.T) {
+		conn := open(t, RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second})
+		tx := lock(t)
+		defer tx.Rollback()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := conn.Write.Table("jobs").Context(ctx).Insert(map[string]any{"name": "e"})
+		if !errors.Is(err, ErrBusy) {
+			t.Errorf("expected ErrBusy, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("expected cancellation to stop the backoff, took %v", elapsed)
+		}
+	})
+
+	t.Run("Backoff bounds", func(t *testing.T) {
+		r := newRetrier(RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 40 * time.Millisecond})
+		for attempt, limit := range map[int]time.Duration{1: 10, 2: 20, 3: 40, 8: 40} {
+			limit *= time.Millisecond
+			for range 20 {
+				if d := r.backoff(attempt); d < limit/2 || d > limit {
+					t.Errorf("attempt %d: backoff %v outside [%v, %v]", attempt, d, limit/2, limit)
+				}
+			}
+		}
+	})
+}
+
+func TestGroupCommit(t *testing.T) {
+	db := setupTestDB(t)
+	db.SetMaxOpenConns(1)
+
+	conn := NewConnector(db, db, Config{GroupCommit: GroupCommit{MaxBatch: 50, MaxLatency: 20 * time.Millisecond}})
+	defer conn.Close()
+
+	err := conn.Write.Table("events").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "name", Type: "TEXT", IsUnique: true},
+	)
+	if err != nil {
+		t.Fatalf("create through group commit connector failed: %v", err)
+	}
+
+	t.Run("Concurrent inserts share transactions", func(t *testing.T) {
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		ids := make(map[int64]bool)
+		errs := make(chan error, 100)
+
+		for i := range 100 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				id, err := conn.Write.Table("events").Insert(map[string]any{"name": fmt.Sprintf("e%d", i)})
+				if err != nil {
+					errs <- err
+					return
+				}
+				mu.Lock()
+				ids[id] = true
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Errorf("insert failed: %v", err)
+		}
+		if len(ids) != 100 {
+			t.Errorf("expected 100 distinct ids, got %d", len(ids))
+		}
+
+		stats := conn.Write.Queue.stats()
+		if stats.Writes != 100 {
+			t.Errorf("expected 100 grouped writes, got %d", stats.Writes)
+		}
+		if stats.Batches >= 100 {
+			t.Errorf("expected writes to be grouped, got %d batches", stats.Batches)
+		}
+	})
+
+	t.Run("Failed job does not discard its batch", func(t *testing.T) {
+		var wg sync.WaitGroup
+		results := make([]error, 3)
+		for i, name := range []string{"dup-a", "e0", "dup-b"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, results[i] = conn.Write.Table("events").Insert(map[string]any{"name": name})
+			}()
+		}
+		wg.Wait()
+
+		if results[0] != nil || results[2] != nil {
+			t.Errorf("expected other jobs to commit, got %v", results)
+		}
+		if !errors.Is(results[1], ErrUniqueViolation) {
+			t.Errorf("expected ErrUniqueViolation, got %v", results[1])
+		}
+
+		count, _ := conn.Read.Table("events").WhereIn("name", []any{"dup-a", "dup-b"}).Count()
+		if count != 2 {
+			t.Errorf("expected 2 committed rows, got %d", count)
+		}
+	})
+
+	t.Run("Update and Delete are grouped", func(t *testing.T) {
+		before := conn.Write.Queue.stats().Writes
+
+		if _, err := conn.Write.Table("events").WhereEq("name", "dup-a").Update(map[string]any{"name": "dup-c"}); err != nil {
+			t.Fatalf("update failed: %v", err)
+		}
+		if _, err := conn.Write.Table("events").WhereEq("name", "dup-b").Delete(); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+
+		if after := conn.Write.Queue.stats().Writes; after != before+2 {
+			t.Errorf("expected 2 more grouped writes, got %d", after-before)
+		}
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := conn.Write.Table("events").Context(ctx).Insert(map[string]any{"name": "cancelled"})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Job context does not interrupt the batch", func(t *testing.T) {
+		job := func(ctx context.Context, name string) *writeJob {
+			return &writeJob{
+				ctx:   ctx,
+				query: `INSERT INTO "events" ("name") VALUES (?)`,
+				args:  []any{name},
+				done:  make(chan writeResult, 1),
+			}
+		}
+		batch := []*writeJob{
+			job(context.Background(), "batch-a"),
+			job(doneContext{context.Background()}, "batch-b"),
+			job(context.Background(), "batch-c"),
+		}
+		conn.Write.Queue.commit(batch)
+
+		for _, j := range batch {
+			if r := <-j.done; r.err != nil {
+				t.Errorf("expected job to commit, got %v", r.err)
+			}
+		}
+		count, _ := conn.Read.Table("events").WhereIn("name", []any{"batch-a", "batch-b", "batch-c"}).Count()
+		if count != 3 {
+			t.Errorf("expected 3 committed rows, got %d", count)
+		}
+	})
+
+	t.Run("Closed queue rejects writes", func(t *testing.T) {
+		conn.Write.Queue.close()
+
+		_, err := conn.Write.Table("events").Insert(map[string]any{"name": "late"})
+		if err == nil || !strings.Contains(err.Error(), "closed") {
+			t.Errorf("expected closed queue error, got %v", err)
+		}
+	})
+}
+
+// * passes the pre-run check but is done once the statement starts
+type doneContext struct {
+	context.Context
+}
+
+func (doneContext) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+func TestIsGroupable(t *testing.T) {
+	tests := map[string]bool{
+		`INSERT INTO "t" ("a") VALUES (?)`: true,
+		`  update "t" SET "a" = ?`:         true,
+		`DELETE FROM "t"`:                  true,
+		`CREATE TABLE "t" ("a" TEXT)`:      false,
+		`PRAGMA wal_checkpoint(PASSIVE)`:   false,
+		`VACUUM`:                           false,
+	}
+
+	for query, expected := range tests {
+		if got := isGroupable(query); got != expected {
+			t.Errorf("isGroupable(%q) = %v, expected %v", query, got, expected)
+		}
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	conn := NewConnector(db, db, Config{})
+	conn.Write.Table("posts").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "title", Type: "TEXT"},
+		Column{Name: "deleted_at", Type: "TEXT", IsNullable: true},
+	)
+	if err := conn.SoftDelete("posts", "deleted_at"); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	conn.Write.Table("posts").InsertBatch([]map[string]any{
+		{"title": "a"}, {"title": "b"}, {"title": "c"},
+	})
+
+	t.Run("Delete stamps the column", func(t *testing.T) {
+		affected, err := conn.Write.Table("posts").WhereEq("title", "a").Delete()
+		if err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+		if affected != 1 {
+			t.Errorf("expected 1 row, got %d", affected)
+		}
+
+		var deletedAt sql.NullString
+		db.QueryRow(`SELECT deleted_at FROM posts WHERE title = 'a'`).Scan(&deletedAt)
+		if !deletedAt.Valid {
+			t.Error("expected row to remain with deleted_at set")
+		}
+	})
+
+	t.Run("Reads skip trashed rows", func(t *testing.T) {
+		count, _ := conn.Read.Table("posts").Count()
+		if count != 2 {
+			t.Errorf("expected 2 rows, got %d", count)
+		}
+
+		var titles []struct {
+			Title string `db:"title"`
+		}
+		conn.Read.Table("posts").Select("title").OrderBy("id").Bind(&titles).Get()
+		if len(titles) != 2 || titles[0].Title != "b" {
+			t.Errorf("unexpected rows: %+v", titles)
+		}
+	})
+
+	t.Run("OR conditions cannot reach trashed rows", func(t *testing.T) {
+		count, _ := conn.Read.Table("posts").WhereEq("title", "a").OrWhere(`"title" = ?`, "b").Count()
+		if count != 1 {
+			t.Errorf("expected 1 row, got %d", count)
+		}
+
+		query, _, _ := conn.Read.Table("posts").WhereEq("title", "a").OrWhere(`"title" = ?`, "b").ToSQL()
+		if !strings.Contains(query, `WHERE ("title" = ? OR "title" = ?) AND "posts"."deleted_at" IS NULL`) {
+			t.Errorf("unexpected query: %s", query)
+		}
+	})
+
+	t.Run("WithTrashed and OnlyTrashed", func(t *testing.T) {
+		if count, _ := conn.Read.Table("posts").WithTrashed().Count(); count != 3 {
+			t.Errorf("expected 3 rows with trashed, got %d", count)
+		}
+		if count, _ := conn.Read.Table("posts").OnlyTrashed().Count(); count != 1 {
+			t.Errorf("expected 1 trashed row, got %d", count)
+		}
+	})
+
+	t.Run("Update ignores trashed rows", func(t *testing.T) {
+		affected, _ := conn.Write.Table("posts").Update(map[string]any{"title": "x"})
+		if affected != 2 {
+			t.Errorf("expected 2 rows updated, got %d", affected)
+		}
+		conn.Write.Table("posts").WhereEq("id", 2).Update(map[string]any{"title": "b"})
+		conn.Write.Table("posts").WhereEq("id", 3).Update(map[string]any{"title": "c"})
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		affected, err := conn.Write.Table("posts").WhereEq("title", "a").Restore()
+		if err != nil {
+			t.Fatalf("restore failed: %v", err)
+		}
+		if affected != 1 {
+			t.Errorf("expected 1 row restored, got %d", affected)
+		}
+		if count, _ := conn.Read.Table("posts").Count(); count != 3 {
+			t.Errorf("expected 3 rows after restore, got %d", count)
+		}
+	})
+
+	t.Run("ForceDelete removes rows", func(t *testing.T) {
+		conn.Write.Table("posts").WhereEq("title", "c").Delete()
+
+		affected, err := conn.Write.Table("posts").WhereEq("title", "c").ForceDelete()
+		if err != nil {
+			t.Fatalf("force delete failed: %v", err)
+		}
+		if affected != 1 {
+			t.Errorf("expected trashed row to be removed, got %d", affected)
+		}
+		if count, _ := conn.Read.Table("posts").WithTrashed().Count(); count != 2 {
+			t.Errorf("expected 2 rows left, got %d", count)
+		}
+	})
+
+	t.Run("Delete still requires WHERE", func(t *testing.T) {
+		if _, err := conn.Write.Table("posts").Delete(); err == nil {
+			t.Error("expected error for delete without where")
+		}
+	})
+
+	t.Run("Restore on unregistered table", func(t *testing.T) {
+		if _, err := conn.Write.Table("other").WhereEq("id", 1).Restore(); err == nil {
+			t.Error("expected error for unregistered table")
+		}
+	})
+
+	t.Run("Unregistered tables are unaffected", func(t *testing.T) {
+		query, _, _ := conn.Read.Table("other").WhereEq("id", 1).ToSQL()
+		if query != `SELECT * FROM "other" WHERE "id" = ?` {
+			t.Errorf("unexpected query: %s", query)
+		}
+	})
+}
+
+func TestTimestamps(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	conn := NewConnector(db, db, Config{})
+	conn.Write.Table("items").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "name", Type: "TEXT"},
+		Column{Name: "stock", Type: "INTEGER", Default: 0},
+		Column{Name: "created_at", Type: "INTEGER", IsNullable: true},
+		Column{Name: "updated_at", Type: "INTEGER", IsNullable: true},
+		Column{Name: "deleted_at", Type: "INTEGER", IsNullable: true},
+	)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := conn.Timestamps("items", Timestamps{
+		CreatedAt: "created_at",
+		UpdatedAt: "updated_at",
+		Now:       func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	stamps := func(id int64) (sql.NullInt64, sql.NullInt64) {
+		var created, updated sql.NullInt64
+		db.QueryRow(`SELECT created_at, updated_at FROM items WHERE id = ?`, id).Scan(&created, &updated)
+		return created, updated
+	}
+
+	t.Run("Insert sets both columns", func(t *testing.T) {
+		data := map[string]any{"name": "a"}
+		id, err := conn.Write.Table("items").Insert(data)
+		if err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+
+		created, updated := stamps(id)
+		if created.Int64 != now.Unix() || updated.Int64 != now.Unix() {
+			t.Errorf("expected %d, got %v / %v", now.Unix(), created, updated)
+		}
+		if len(data) != 1 {
+			t.Error("expected caller map to be left untouched")
+		}
+	})
+
+	t.Run("InsertBatch stamps every row", func(t *testing.T) {
+		conn.Write.Table("items").InsertBatch([]map[string]any{{"name": "b"}, {"name": "c"}})
+
+		count, _ := conn.Read.Table("items").WhereEq("created_at", now.Unix()).Count()
+		if count != 3 {
+			t.Errorf("expected 3 stamped rows, got %d", count)
+		}
+	})
+
+	t.Run("Explicit value wins", func(t *testing.T) {
+		id, _ := conn.Write.Table("items").Insert(map[string]any{"name": "d", "created_at": 1})
+		if created, _ := stamps(id); created.Int64 != 1 {
+			t.Errorf("expected explicit created_at, got %v", created)
+		}
+	})
+
+	t.Run("Update helpers set updated_at", func(t *testing.T) {
+		now = now.Add(time.Hour)
+
+		conn.Write.Table("items").WhereEq("name", "a").Update(map[string]any{"name": "a2"})
+		conn.Write.Table("items").WhereEq("name", "b").Increase("stock").Update()
+
+		for _, id := range []int64{1, 2} {
+			created, updated := stamps(id)
+			if updated.Int64 != now.Unix() || created.Int64 == now.Unix() {
+				t.Errorf("row %d: expected only updated_at to move, got %v / %v", id, created, updated)
+			}
+		}
+		if _, updated := stamps(3); updated.Int64 == now.Unix() {
+			t.Error("expected untouched row to keep updated_at")
+		}
+	})
+
+	t.Run("Formats", func(t *testing.T) {
+		tests := []struct {
+			format   TimeFormat
+			expected any
+		}{
+			{UnixSeconds, now.Unix()},
+			{UnixMillis, now.UnixMilli()},
+			{RFC3339, "2024-01-02T04:04:05Z"},
+		}
+		for _, tt := range tests {
+			ts := Timestamps{Format: tt.format, Now: func() time.Time { return now }}
+			if got := ts.value(); got != tt.expected {
+				t.Errorf("format %d: expected %v, got %v", tt.format, tt.expected, got)
+			}
+		}
+
+		if err := conn.Timestamps("items", Timestamps{Format: 9}); err == nil {
+			t.Error("expected error for invalid format")
+		}
+	})
+
+	t.Run("Soft delete uses the same clock", func(t *testing.T) {
+		conn.SoftDelete("items", "deleted_at")
+		conn.Write.Table("items").WhereEq("id", 3).Delete()
+
+		var deletedAt sql.NullInt64
+		db.QueryRow(`SELECT deleted_at FROM items WHERE id = 3`).Scan(&deletedAt)
+		if deletedAt.Int64 != now.Unix() {
+			t.Errorf("expected deleted_at %d, got %v", now.Unix(), deletedAt)
+		}
+	})
+
+	t.Run("Loader sets both columns", func(t *testing.T) {
+		loader, err := conn.Write.Table("items").Loader("id", "name")
+		if err != nil {
+			t.Fatalf("loader failed: %v", err)
+		}
+		loader.Add(100, "loaded")
+		if err := loader.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+
+		created, updated := stamps(100)
+		if created.Int64 != now.Unix() || updated.Int64 != now.Unix() {
+			t.Errorf("expected %d, got %v / %v", now.Unix(), created, updated)
+		}
+	})
+
+	t.Run("Upsert DO UPDATE refreshes updated_at", func(t *testing.T) {
+		conn.Write.Table("tags").Create(
+			Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+			Column{Name: "name", Type: "TEXT", IsUnique: true},
+			Column{Name: "hits", Type: "INTEGER", Default: 0},
+			Column{Name: "created_at", Type: "INTEGER", IsNullable: true},
+			Column{Name: "updated_at", Type: "INTEGER", IsNullable: true},
+		)
+		clock := time.Unix(100, 0)
+		conn.Timestamps("tags", Timestamps{
+			CreatedAt: "created_at",
+			UpdatedAt: "updated_at",
+			Now:       func() time.Time { return clock },
+		})
+
+		upsert := func() {
+			_, err := conn.Write.Table("tags").
+				OnConflict("name").
+				DoUpdateSet(`"hits" = "hits" + 1`).
+				Insert(map[string]any{"name": "go"})
+			if err != nil {
+				t.Fatalf("upsert failed: %v", err)
+			}
+		}
+		upsert()
+		clock = time.Unix(200, 0)
+		upsert()
+
+		var created, updated, hits int64
+		db.QueryRow(`SELECT created_at, updated_at, hits FROM tags WHERE name = 'go'`).Scan(&created, &updated, &hits)
+		if hits != 1 || created != 100 || updated != 200 {
+			t.Errorf("expected hits 1, created 100, updated 200, got %d / %d / %d", hits, created, updated)
+		}
+	})
+}
+
+type tenantKey struct{}
+
+func TestScopes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	conn := NewConnector(db, db, Config{})
+	conn.Write.Table("projects").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "tenant_id", Type: "INTEGER"},
+		Column{Name: "name", Type: "TEXT"},
+	)
+	if err := conn.Scope(TenantScope("tenant_id", tenantKey{}), "projects"); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	tenant1 := context.WithValue(context.Background(), tenantKey{}, 1)
+	tenant2 := context.WithValue(context.Background(), tenantKey{}, 2)
+
+	t.Run("Insert sets the tenant column", func(t *testing.T) {
+		conn.Write.Table("projects").Context(tenant1).Insert(map[string]any{"name": "a"})
+		conn.Write.Table("projects").Context(tenant1).InsertBatch([]map[string]any{{"name": "b"}, {"name": "c"}})
+		conn.Write.Table("projects").Context(tenant2).Insert(map[string]any{"name": "d"})
+
+		var count int
+		db.QueryRow(`SELECT COUNT(*) FROM projects WHERE tenant_id = 1`).Scan(&count)
+		if count != 3 {
+			t.Errorf("expected 3 rows for tenant 1, got %d", count)
+		}
+	})
+
+	t.Run("Reads are filtered", func(t *testing.T) {
+		if count, _ := conn.Read.Table("projects").Context(tenant1).Count(); count != 3 {
+			t.Errorf("expected 3 rows, got %d", count)
+		}
+		if count, _ := conn.Read.Table("projects").Context(tenant2).WhereEq("name", "a").OrWhere(`"name" = ?`, "d").Count(); count != 1 {
+			t.Errorf("expected OR to stay inside tenant 2, got %d", count)
+		}
+		if count, _ := conn.Read.Table("projects").Context(tenant2).Where(`"name" = ? OR "name" = ?`, "a", "d").Count(); count != 1 {
+			t.Errorf("expected raw OR to stay inside tenant 2, got %d", count)
+		}
+
+		var project struct {
+			ID       int    `db:"id"`
+			TenantID int    `db:"tenant_id"`
+			Name     string `db:"name"`
+		}
+		if _, err := conn.Read.Table("projects").Context(tenant2).Bind(&project).First(); err != nil {
+			t.Fatalf("first failed: %v", err)
+		}
+		if project.Name != "d" {
+			t.Errorf("expected tenant 2 row, got %+v", project)
+		}
+	})
+
+	t.Run("Update and Delete are filtered", func(t *testing.T) {
+		affected, _ := conn.Write.Table("projects").Context(tenant2).Update(map[string]any{"name": "z"})
+		if affected != 1 {
+			t.Errorf("expected 1 row updated, got %d", affected)
+		}
+
+		affected, _ = conn.Write.Table("projects").Context(tenant2).WhereEq("name", "a").Delete()
+		if affected != 0 {
+			t.Errorf("expected other tenant's row to be untouched, got %d", affected)
+		}
+	})
+
+	t.Run("Missing value fails closed", func(t *testing.T) {
+		_, err := conn.Read.Table("projects").Count()
+		if !errors.Is(err, ErrScopeMissing) {
+			t.Errorf("expected ErrScopeMissing, got %v", err)
+		}
+
+		_, err = conn.Write.Table("projects").Insert(map[string]any{"name": "x"})
+		if !errors.Is(err, ErrScopeMissing) {
+			t.Errorf("expected ErrScopeMissing on insert, got %v", err)
+		}
+	})
+
+	t.Run("WithoutScopes bypasses", func(t *testing.T) {
+		if count, _ := conn.Read.Table("projects").WithoutScopes().Count(); count != 4 {
+			t.Errorf("expected 4 rows, got %d", count)
+		}
+		if count, _ := conn.Read.Table("projects").WithoutScopes("tenant").Count(); count != 4 {
+			t.Errorf("expected 4 rows by name, got %d", count)
+		}
+		if _, err := conn.Read.Table("projects").WithoutScopes("other").Count(); !errors.Is(err, ErrScopeMissing) {
+			t.Errorf("expected unrelated name to keep the scope, got %v", err)
+		}
+	})
+
+	t.Run("Compiled query rebinds per context", func(t *testing.T) {
+		q, err := conn.Read.Table("projects").Context(tenant1).Select("name").OrderBy("id").Compile()
+		if err != nil {
+			t.Fatalf("compile failed: %v", err)
+		}
+
+		var names []struct {
+			Name string `db:"name"`
+		}
+		if err := q.Context(tenant2).Bind(&names); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if len(names) != 1 || names[0].Name != "z" {
+			t.Errorf("expected tenant 2 rows, got %+v", names)
+		}
+
+		if err := q.Context(context.Background()).Bind(&names); !errors.Is(err, ErrScopeMissing) {
+			t.Errorf("expected ErrScopeMissing, got %v", err)
+		}
+	})
+
+	t.Run("Writes cannot cross tenants", func(t *testing.T) {
+		conn.Write.Table("accounts").Create(
+			Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+			Column{Name: "tenant_id", Type: "INTEGER"},
+			Column{Name: "email", Type: "TEXT", IsUnique: true},
+			Column{Name: "name", Type: "TEXT", IsNullable: true},
+		)
+		if err := conn.Scope(TenantScope("tenant_id", tenantKey{}), "accounts"); err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+		tenantOf := func(email string) int {
+			var tenant int
+			db.QueryRow(`SELECT tenant_id FROM accounts WHERE email = ?`, email).Scan(&tenant)
+			return tenant
+		}
+
+		if _, err := conn.Write.Table("accounts").Context(tenant1).Insert(map[string]any{"email": "a@x", "tenant_id": 2}); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+		if tenant := tenantOf("a@x"); tenant != 1 {
+			t.Errorf("expected insert to stay in tenant 1, got %d", tenant)
+		}
+
+		if _, err := conn.Write.Table("accounts").Context(tenant1).Update(map[string]any{"tenant_id": 2}); err != nil {
+			t.Fatalf("update failed: %v", err)
+		}
+		if tenant := tenantOf("a@x"); tenant != 1 {
+			t.Errorf("expected update to stay in tenant 1, got %d", tenant)
+		}
+
+		_, err := conn.Write.Table("accounts").Context(tenant2).
+			OnConflict("email").
+			DoUpdate(map[string]any{"name": "hijacked", "tenant_id": 2}).
+			Insert(map[string]any{"email": "a@x"})
+		if err != nil {
+			t.Fatalf("upsert failed: %v", err)
+		}
+		var name sql.NullString
+		db.QueryRow(`SELECT name FROM accounts WHERE email = ?`, "a@x").Scan(&name)
+		if name.Valid || tenantOf("a@x") != 1 {
+			t.Errorf("expected other tenant's row to be untouched, got %v / %d", name, tenantOf("a@x"))
+		}
+
+		conn.Write.Table("accounts").Context(tenant1).
+			OnConflict("email").
+			DoUpdate(map[string]any{"name": "own"}).
+			DoUpdateWhere(`"name" IS NULL OR "name" = ?`, "").
+			Insert(map[string]any{"email": "a@x"})
+		db.QueryRow(`SELECT name FROM accounts WHERE email = ?`, "a@x").Scan(&name)
+		if name.String != "own" {
+			t.Errorf("expected own row to be updated, got %v", name)
+		}
+
+		loader, err := conn.Write.Table("accounts").Context(tenant2).Loader("email", "tenant_id")
+		if err != nil {
+			t.Fatalf("loader failed: %v", err)
+		}
+		loader.Add("b@x", 1)
+		if err := loader.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+		if tenant := tenantOf("b@x"); tenant != 2 {
+			t.Errorf("expected loader row in tenant 2, got %d", tenant)
+		}
+
+		if _, err := conn.Write.Table("accounts").Loader("email"); !errors.Is(err, ErrScopeMissing) {
+			t.Errorf("expected ErrScopeMissing from Loader, got %v", err)
+		}
+	})
+
+	t.Run("Replace cannot delete another tenant's row", func(t *testing.T) {
+		replace := func() *Builder {
+			return conn.Write.Table("accounts").Context(tenant2).Conflict(Replace)
+		}
+		if _, err := replace().Insert(map[string]any{"email": "a@x"}); !errors.Is(err, ErrScopeReplace) {
+			t.Errorf("expected ErrScopeReplace on Insert, got %v", err)
+		}
+		if _, err := replace().InsertBatch([]map[string]any{{"email": "a@x"}}); !errors.Is(err, ErrScopeReplace) {
+			t.Errorf("expected ErrScopeReplace on InsertBatch, got %v", err)
+		}
+		if _, err := replace().Loader("email"); !errors.Is(err, ErrScopeReplace) {
+			t.Errorf("expected ErrScopeReplace on Loader, got %v", err)
+		}
+
+		count, err := conn.Read.Table("accounts").Context(tenant1).WhereEq("email", "a@x").Count()
+		if err != nil || count != 1 {
+			t.Errorf("expected tenant 1 row to survive, got %d (%v)", count, err)
+		}
+
+		if _, err := conn.Write.Table("accounts").WithoutScopes().Conflict(Replace).
+			Insert(map[string]any{"email": "c@x", "tenant_id": 2}); err != nil {
+			t.Errorf("expected Replace to be allowed with WithoutScopes, got %v", err)
+		}
+	})
+
+	t.Run("Invalid registration", func(t *testing.T) {
+		if err := conn.Scope(Scope{Name: "x", Column: "id"}, "projects"); err == nil {
+			t.Error("expected error without value func")
+		}
+		if err := conn.Scope(TenantScope("tenant_id", tenantKey{})); err == nil {
+			t.Error("expected error without tables")
+		}
+	})
+}
+
+func TestBuilderPaginate(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	builder := NewBuilder(db)
+	builder.Table("scores").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "score", Type: "INTEGER"},
+	)
+	rows := make([]map[string]any, 25)
+	for i := range rows {
+		rows[i] = map[string]any{"score": i % 7}
+	}
+	builder.Table("scores").InsertBatch(rows)
+
+	type score struct {
+		ID    int64 `db:"id"`
+		Score int   `db:"score"`
+	}
+
+	var all []score
+	builder.Table("scores").OrderBy("score", Desc).OrderBy("id").Bind(&all).Get()
+
+	page := func(t *testing.T, cursor string) ([]score, *Cursor) {
+		t.Helper()
+		var items []score
+		c, err := builder.Table("scores").OrderBy("score", Desc).OrderBy("id").Bind(&items).Paginate(cursor, 10)
+		if err != nil {
+			t.Fatalf("paginate failed: %v", err)
+		}
+		return items, c
+	}
+
+	ids := func(items []score) []int64 {
+		result := make([]int64, len(items))
+		for i, item := range items {
+			result[i] = item.ID
+		}
+		return result
+	}
+
+	t.Run("Forward and backward", func(t *testing.T) {
+		first, c1 := page(t, "")
+		if !slices.Equal(ids(first), ids(all[:10])) || c1.Next == "" || c1.Prev != "" {
+			t.Fatalf("unexpected first page: %v %+v", ids(first), c1)
+		}
+
+		second, c2 := page(t, c1.Next)
+		if !slices.Equal(ids(second), ids(all[10:20])) || c2.Next == "" || c2.Prev == "" {
+			t.Fatalf("unexpected second page: %v %+v", ids(second), c2)
+		}
+
+		last, c3 := page(t, c2.Next)
+		if !slices.Equal(ids(last), ids(all[20:])) || c3.Next != "" || c3.Prev == "" {
+			t.Fatalf("unexpected last page: %v %+v", ids(last), c3)
+		}
+
+		back, c4 := page(t, c3.Prev)
+		if !slices.Equal(ids(back), ids(second)) || c4.Next == "" || c4.Prev == "" {
+			t.Fatalf("unexpected page walking back: %v %+v", ids(back), c4)
+		}
+
+		start, c5 := page(t, c4.Prev)
+		if !slices.Equal(ids(start), ids(first)) || c5.Prev != "" || c5.Next == "" {
+			t.Fatalf("unexpected first page walking back: %v %+v", ids(start), c5)
+		}
+	})
+
+	t.Run("Stable under concurrent inserts", func(t *testing.T) {
+		_, c1 := page(t, "")
+		builder.Table("scores").Insert(map[string]any{"score": 100})
+
+		second, _ := page(t, c1.Next)
+		if !slices.Equal(ids(second), ids(all[10:20])) {
+			t.Errorf("expected second page unchanged, got %v", ids(second))
+		}
+	})
+
+	t.Run("OR filter stays grouped", func(t *testing.T) {
+		var filtered []score
+		builder.Table("scores").WhereEq("score", 1).OrWhereEq("score", 2).OrderBy("id").Bind(&filtered).Get()
+
+		var walked []int64
+		cursor := ""
+		for range 10 {
+			var items []score
+			c, err := builder.Table("scores").WhereEq("score", 1).OrWhereEq("score", 2).OrderBy("id").Bind(&items).Paginate(cursor, 3)
+			if err != nil {
+				t.Fatalf("paginate failed: %v", err)
+			}
+			walked = append(walked, ids(items)...)
+			if c.Next == "" {
+				break
+			}
+			cursor = c.Next
+		}
+		if !slices.Equal(walked, ids(filtered)) {
+			t.Errorf("expected %v, got %v", ids(filtered), walked)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var items []score
+		if _, err := builder.Table("scores").Bind(&items).Paginate("", 10); err == nil {
+			t.Error("expected error without OrderBy")
+		}
+		if _, err := builder.Table("scores").OrderBy("id").Bind(&items).Paginate("not-a-cursor", 10); err == nil {
+			t.Error("expected error for invalid cursor")
+		}
+		if _, err := builder.Table("scores").OrderBy("id").Select("score").Bind(&items).Paginate("", 10); err == nil {
+			t.Error("expected error when the order column is not selected")
+		}
+		if _, err := builder.Table("scores").OrderBy("id").Paginate("", 10); err == nil {
+			t.Error("expected error without Bind target")
+		}
+	})
+}
+
+func TestBuilderPage(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	builder := NewBuilder(db)
+	builder.Table("articles").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "category", Type: "TEXT"},
+	)
+	rows := make([]map[string]any, 23)
+	for i := range rows {
+		rows[i] = map[string]any{"category": fmt.Sprintf("c%d", i%4)}
+	}
+	builder.Table("articles").InsertBatch(rows)
+
+	type article struct {
+		ID       int64  `db:"id"`
+		Category string `db:"category"`
+	}
+
+	t.Run("Middle page", func(t *testing.T) {
+		var items []article
+		p, err := builder.Table("articles").OrderBy("id").Bind(&items).Page(2, 10)
+		if err != nil {
+			t.Fatalf("page failed: %v", err)
+		}
+		if len(items) != 10 || items[0].ID != 11 || items[0].Category == "" {
+			t.Errorf("unexpected items: %+v", items)
+		}
+		if p.Total != 23 || p.Pages != 3 || !p.HasNext || !p.HasPrev {
+			t.Errorf("unexpected metadata: %+v", p)
+		}
+		if p.Items != &items {
+			t.Error("expected Items to reference the bind target")
+		}
+	})
+
+	t.Run("Last page", func(t *testing.T) {
+		var items []article
+		p, _ := builder.Table("articles").OrderBy("id").Bind(&items).Page(3, 10)
+		if len(items) != 3 || p.HasNext || !p.HasPrev {
+			t.Errorf("unexpected last page: %d items, %+v", len(items), p)
+		}
+	})
+
+	t.Run("Past the end still reports total", func(t *testing.T) {
+		items := []article{{ID: 99}}
+		p, err := builder.Table("articles").WhereEq("category", "c1").Bind(&items).Page(5, 10)
+		if err != nil {
+			t.Fatalf("page failed: %v", err)
+		}
+		if len(items) != 0 {
+			t.Errorf("expected empty page, got %+v", items)
+		}
+		if p.Total != 6 || p.Pages != 1 || p.HasNext || !p.HasPrev {
+			t.Errorf("unexpected metadata: %+v", p)
+		}
+	})
+
+	t.Run("Grouped query counts groups", func(t *testing.T) {
+		var items []struct {
+			Category string `db:"category"`
+		}
+		p, err := builder.Table("articles").Select("category").GroupBy("category").Bind(&items).Page(9, 2)
+		if err != nil {
+			t.Fatalf("page failed: %v", err)
+		}
+		if p.Total != 4 || p.Pages != 2 {
+			t.Errorf("expected 4 groups in 2 pages, got %+v", p)
+		}
+	})
+
+	t.Run("Empty table", func(t *testing.T) {
+		var items []article
+		p, _ := builder.Table("articles").WhereEq("category", "none").Bind(&items).Page(1, 10)
+		if p.Total != 0 || p.Pages != 0 || p.HasNext || p.HasPrev {
+			t.Errorf("unexpected metadata: %+v", p)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var items []article
+		if _, err := builder.Table("articles").Bind(&items).Page(0, 10); err == nil {
+			t.Error("expected error for page 0")
+		}
+		if _, err := builder.Table("articles").Page(1, 10); err == nil {
+			t.Error("expected error without Bind target")
+		}
+	})
+}
+
+func TestIter(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	builder := NewBuilder(db)
+	builder.Table("logs").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "message", Type: "TEXT"},
+	)
+	rows := make([]map[string]any, 50)
+	for i := range rows {
+		rows[i] = map[string]any{"message": fmt.Sprintf("m%d", i)}
+	}
+	builder.Table("logs").InsertBatch(rows)
+
+	type entry struct {
+		ID      int64  `db:"id"`
+		Message string `db:"message"`
+	}
+
+	t.Run("Streams every row", func(t *testing.T) {
+		var count int
+		for item, err := range Iter[entry](builder.Table("logs").OrderBy("id")) {
+			if err != nil {
+				t.Fatalf("iteration failed: %v", err)
+			}
+			count++
+			if item.Message != fmt.Sprintf("m%d", item.ID-1) {
+				t.Errorf("unexpected row: %+v", item)
+			}
+		}
+		if count != 50 {
+			t.Errorf("expected 50 rows, got %d", count)
+		}
+	})
+
+	t.Run("Break closes rows", func(t *testing.T) {
+		for range Iter[entry](builder.Table("logs")) {
+			break
+		}
+		if inUse := db.Stats().InUse; inUse != 0 {
+			t.Errorf("expected connection to be released, %d in use", inUse)
+		}
+	})
+
+	t.Run("Context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var count int
+		var iterErr error
+		for _, err := range Iter[entry](builder.Table("logs").Context(ctx)) {
+			if err != nil {
+				iterErr = err
+				break
+			}
+			count++
+			if count == 5 {
+				cancel()
+			}
+		}
+		if !errors.Is(iterErr, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v after %d rows", iterErr, count)
+		}
+	})
+
+	t.Run("Errors are yielded", func(t *testing.T) {
+		for _, err := range Iter[entry](builder.Table("missing")) {
+			if err == nil {
+				t.Error("expected error for missing table")
+			}
+		}
+		for _, err := range Iter[int](builder.Table("logs")) {
+			if err == nil {
+				t.Error("expected error for non-struct type")
+			}
+		}
+	})
+
+	t.Run("Each", func(t *testing.T) {
+		var messages []string
+		err := builder.Table("logs").WhereLt("id", 4).OrderBy("id").Each(func(row map[string]any) error {
+			messages = append(messages, row["message"].(string))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("each failed: %v", err)
+		}
+		if !slices.Equal(messages, []string{"m0", "m1", "m2"}) {
+			t.Errorf("unexpected messages: %v", messages)
+		}
+	})
+
+	t.Run("Each stops on error", func(t *testing.T) {
+		stop := errors.New("stop")
+		var count int
+		err := builder.Table("logs").Each(func(row map[string]any) error {
+			count++
+			return stop
+		})
+		if !errors.Is(err, stop) || count != 1 {
+			t.Errorf("expected to stop after 1 row, got %d rows and %v", count, err)
+		}
+	})
+
+	t.Run("Each normalizes TEXT bytes", func(t *testing.T) {
+		id, _ := builder.Table("logs").Insert(map[string]any{"message": []byte("bytes")})
+		err := builder.Table("logs").WhereEq("id", id).Each(func(row map[string]any) error {
+			if message, ok := row["message"].(string); !ok || message != "bytes" {
+				t.Errorf("expected string, got %T %v", row["message"], row["message"])
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("each failed: %v", err)
+		}
+	})
+}
+
+func TestChunk(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	builder := NewBuilder(db)
+	builder.Table("orders").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "status", Type: "TEXT"},
+	)
+	rows := make([]map[string]any, 25)
+	for i := range rows {
+		rows[i] = map[string]any{"status": "pending"}
+	}
+	builder.Table("orders").InsertBatch(rows)
+
+	type order struct {
+		ID     int64  `db:"id"`
+		Status string `db:"status"`
+	}
+
+	t.Run("ChunkByID walks every row once", func(t *testing.T) {
+		var sizes []int
+		var ids []int64
+		err := ChunkByID(builder.Table("orders"), "id", 10, func(batch []order) error {
+			sizes = append(sizes, len(batch))
+			for _, o := range batch {
+				ids = append(ids, o.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("chunk failed: %v", err)
+		}
+		if !slices.Equal(sizes, []int{10, 10, 5}) {
+			t.Errorf("unexpected batch sizes: %v", sizes)
+		}
+		if len(ids) != 25 || ids[0] != 1 || ids[24] != 25 {
+			t.Errorf("unexpected ids: %v", ids)
+		}
+	})
+
+	t.Run("Callback may update the filtered rows", func(t *testing.T) {
+		var processed int
+		err := ChunkByID(builder.Table("orders").WhereEq("status", "pending"), "id", 10, func(batch []order) error {
+			for _, o := range batch {
+				if _, err := builder.Table("orders").WhereEq("id", o.ID).Update(map[string]any{"status": "done"}); err != nil {
+					return err
+				}
+			}
+			processed += len(batch)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("chunk failed: %v", err)
+		}
+		if processed != 25 {
+			t.Errorf("expected 25 rows processed, got %d", processed)
+		}
+		if count, _ := builder.Table("orders").WhereEq("status", "pending").Count(); count != 0 {
+			t.Errorf("expected no pending rows, got %d", count)
+		}
+	})
+
+	t.Run("Chunk uses ROWID", func(t *testing.T) {
+		var total int
+		err := Chunk(builder.Table("orders").Select("status"), 7, func(batch []struct {
+			Status string `db:"status"`
+		}) error {
+			total += len(batch)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("chunk failed: %v", err)
+		}
+		if total != 25 {
+			t.Errorf("expected 25 rows, got %d", total)
+		}
+	})
+
+	t.Run("OR filter stays grouped", func(t *testing.T) {
+		var ids []int64
+		err := ChunkByID(builder.Table("orders").WhereEq("id", 1).OrWhereEq("id", 2), "id", 1, func(batch []order) error {
+			if len(ids) > 5 {
+				return errors.New("walk did not end")
+			}
+			ids = append(ids, batch[0].ID)
+			return nil
+		})
+		if err != nil || !slices.Equal(ids, []int64{1, 2}) {
+			t.Errorf("expected [1 2], got %v (%v)", ids, err)
+		}
+
+		var total int
+		err = Chunk(builder.Table("orders").WhereEq("id", 1).OrWhereEq("id", 2), 1, func(batch []order) error {
+			if total > 5 {
+				return errors.New("walk did not end")
+			}
+			total += len(batch)
+			return nil
+		})
+		if err != nil || total != 2 {
+			t.Errorf("expected 2 rows, got %d (%v)", total, err)
+		}
+	})
+
+	t.Run("Stops on error", func(t *testing.T) {
+		stop := errors.New("stop")
+		var calls int
+		err := ChunkByID(builder.Table("orders"), "id", 10, func(batch []order) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) || calls != 1 {
+			t.Errorf("expected to stop after 1 call, got %d and %v", calls, err)
+		}
+	})
+
+	t.Run("NULL key fails", func(t *testing.T) {
+		builder.Table("codes").Create(
+			Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+			Column{Name: "code", Type: "TEXT", IsNullable: true, IsUnique: true},
+		)
+		builder.Table("codes").InsertBatch([]map[string]any{{"code": nil}, {"code": nil}, {"code": "a"}})
+
+		type code struct {
+			ID   int64   `db:"id"`
+			Code *string `db:"code"`
+		}
+		var seen int
+		err := ChunkByID(builder.Table("codes"), "code", 2, func(batch []code) error {
+			seen += len(batch)
+			return nil
+		})
+		if err == nil || seen != 0 {
+			t.Errorf("expected error before any callback, got %d rows and %v", seen, err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		noop := func([]order) error { return nil }
+		if err := ChunkByID(builder.Table("orders").OrderBy("id"), "id", 10, noop); err == nil {
+			t.Error("expected error when combined with OrderBy")
+		}
+		if err := ChunkByID(builder.Table("orders").Select("status"), "id", 10, noop); err == nil {
+			t.Error("expected error when key column is not selected")
+		}
+		if err := ChunkByID(builder.Table("orders"), "id", 0, noop); err == nil {
+			t.Error("expected error for size 0")
+		}
+	})
+}
+
+func TestBuilderBindDynamic(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	builder := NewBuilder(db)
+	builder.Table("items").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "name", Type: "TEXT"},
+		Column{Name: "data", Type: "BLOB", IsNullable: true},
+	)
+	builder.Table("items").Insert(map[string]any{"name": []byte("alice"), "data": []byte{1, 2}})
+	builder.Table("items").Insert(map[string]any{"name": "bob", "data": nil})
+
+	t.Run("Map", func(t *testing.T) {
+		var row map[string]any
+		if _, err := builder.Table("items").WhereEq("id", 1).Bind(&row).Get(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if name, ok := row["name"].(string); !ok || name != "alice" {
+			t.Errorf("expected TEXT normalized to string, got %T %v", row["name"], row["name"])
+		}
+		if data, ok := row["data"].([]byte); !ok || len(data) != 2 {
+			t.Errorf("expected BLOB kept as bytes, got %T %v", row["data"], row["data"])
+		}
+	})
+
+	t.Run("Map not found", func(t *testing.T) {
+		var row map[string]any
+		_, err := builder.Table("items").WhereEq("id", 99).Bind(&row).Get()
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+	})
+
+	t.Run("Slice of maps", func(t *testing.T) {
+		var rows []map[string]any
+		if _, err := builder.Table("items").OrderBy("id").Bind(&rows).Get(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if len(rows) != 2 || rows[1]["name"] != "bob" || rows[1]["data"] != nil {
+			t.Errorf("unexpected rows: %v", rows)
+		}
+	})
+
+	t.Run("Pluck", func(t *testing.T) {
+		var names []any
+		if _, err := builder.Table("items").Select("name").OrderBy("id").Bind(&names).Get(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if len(names) != 2 || names[0] != "alice" || names[1] != "bob" {
+			t.Errorf("unexpected names: %v", names)
+		}
+
+		var ids []int64
+		if _, err := builder.Table("items").Select("id").OrderBy("id").Bind(&ids).Get(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if len(ids) != 2 || ids[1] != 2 {
+			t.Errorf("unexpected ids: %v", ids)
+		}
+
+		if _, err := builder.Table("items").Bind(&names).Get(); err == nil {
+			t.Error("expected error when plucking several columns")
+		}
+	})
+
+	t.Run("Scalar", func(t *testing.T) {
+		var name string
+		row, err := builder.Table("items").Select("name").WhereEq("id", 2).Bind(&name).First()
+		if err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if row == nil || row.Err() != nil {
+			t.Errorf("expected a usable row when bound, got %v", row)
+		}
+		if name != "bob" {
+			t.Errorf("expected bob, got %q", name)
+		}
+
+		var id int64
+		if _, err := builder.Table("items").Select("id").Bind(&id).Last(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if id != 1 {
+			t.Errorf("expected 1, got %d", id)
+		}
+	})
+
+	t.Run("Get maps struct by column name", func(t *testing.T) {
+		var item struct {
+			Name string `db:"name"`
+			ID   int64  `db:"id"`
+		}
+		if _, err := builder.Table("items").Select("id", "name").OrderBy("id").Bind(&item).Get(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if item.ID != 1 || item.Name != "alice" {
+			t.Errorf("unexpected item: %+v", item)
+		}
+	})
+
+	t.Run("First rejects map target", func(t *testing.T) {
+		var row map[string]any
+		r, err := builder.Table("items").Bind(&row).First()
+		if err == nil || r == nil {
+			t.Errorf("expected error and a non-nil row, got %v / %v", r, err)
+		}
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		q, err := builder.Table("items").Select("name").WhereEq("id", 2).Compile()
+		if err != nil {
+			t.Fatalf("compile failed: %v", err)
+		}
+
+		var row map[string]any
+		if err := q.Bind(&row, 1); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if row["name"] != "alice" {
+			t.Errorf("unexpected row: %v", row)
+		}
+	})
+
+	t.Run("Invalid map", func(t *testing.T) {
+		var row map[string]string
+		if _, err := builder.Table("items").Bind(&row).Get(); err == nil {
+			t.Error("expected error for map[string]string")
+		}
+	})
+}
+
+func TestStructBinding(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	builder := NewBuilder(db)
+	builder.Table("users").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "name", Type: "TEXT"},
+		Column{Name: "bio", Type: "TEXT", IsNullable: true},
+		Column{Name: "age", Type: "INTEGER", IsNullable: true},
+		Column{Name: "secret", Type: "TEXT", IsNullable: true},
+	)
+	builder.Table("posts").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "title", Type: "TEXT"},
+		Column{Name: "user_id", Type: "INTEGER"},
+	)
+	builder.Table("users").Insert(map[string]any{"name": "alice", "bio": "hi", "age": 30, "secret": "x"})
+	builder.Table("users").Insert(map[string]any{"name": "bob"})
+	builder.Table("posts").Insert(map[string]any{"title": "hello", "user_id": 2})
+	if _, err := db.Exec(`CREATE VIEW post_view AS
+		SELECT posts.id, posts.title, users.id AS author_id, users.name AS author_name
+		FROM posts JOIN users ON users.id = posts.user_id`); err != nil {
+		t.Fatalf("create view failed: %v", err)
+	}
+
+	type Base struct {
+		ID int64 `db:"id"`
+	}
+
+	type User struct {
+		Base
+		Name   string
+		Bio    *string       `db:"bio"`
+		Age    sql.NullInt64 `db:"age"`
+		Secret string        `db:"-"`
+	}
+
+	t.Run("Embedded, pointer and null fields", func(t *testing.T) {
+		var users []User
+		if _, err := builder.Table("users").OrderBy("id").Bind(&users).Get(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if len(users) != 2 {
+			t.Fatalf("expected 2 users, got %d", len(users))
+		}
+
+		alice, bob := users[0], users[1]
+		if alice.ID != 1 || alice.Name != "alice" || alice.Bio == nil || *alice.Bio != "hi" || alice.Age.Int64 != 30 {
+			t.Errorf("unexpected alice: %+v", alice)
+		}
+		if alice.Secret != "" {
+			t.Errorf("expected skipped field to stay empty, got %q", alice.Secret)
+		}
+		if bob.ID != 2 || bob.Bio != nil || bob.Age.Valid {
+			t.Errorf("expected NULL columns on bob, got %+v", bob)
+		}
+	})
+
+	t.Run("Embedded pointer", func(t *testing.T) {
+		var user struct {
+			*Base
+			Name string `db:"name"`
+		}
+		if _, err := builder.Table("users").WhereEq("id", 2).Bind(&user).Get(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if user.Base == nil || user.ID != 2 || user.Name != "bob" {
+			t.Errorf("unexpected user: %+v", user)
+		}
+	})
+
+	t.Run("Outer field shadows embedded", func(t *testing.T) {
+		var user struct {
+			Base
+			ID string `db:"id"`
+		}
+		if _, err := builder.Table("users").WhereEq("id", 1).Bind(&user).Get(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if user.ID != "1" || user.Base.ID != 0 {
+			t.Errorf("expected outer field to be bound, got %+v", user)
+		}
+	})
+
+	t.Run("Prefix tag", func(t *testing.T) {
+		type Author struct {
+			ID   int64  `db:"id"`
+			Name string `db:"name"`
+		}
+
+		var post struct {
+			ID     int64   `db:"id"`
+			Title  string  `db:"title"`
+			Author *Author `db:"author_"`
+		}
+		if _, err := builder.Table("post_view").Bind(&post).Get(); err != nil {
+			t.Fatalf("bind failed: %v", err)
+		}
+		if post.ID != 1 || post.Title != "hello" || post.Author == nil || post.Author.ID != 2 || post.Author.Name != "bob" {
+			t.Errorf("unexpected post: %+v", post)
+		}
+	})
+
+	t.Run("Field map is cached", func(t *testing.T) {
+		typ := reflect.TypeFor[User]()
+		if typeFields(typ) != typeFields(typ) {
+			t.Error("expected cached field map")
+		}
+		if _, ok := typeFields(typ).lookup("secret"); ok {
+			t.Error("expected db:\"-\" field to be skipped")
+		}
+		if index, ok := typeFields(typ).lookup("NAME"); !ok || len(index) != 1 {
+			t.Errorf("expected untagged field to match case-insensitively, got %v", index)
+		}
+	})
+}
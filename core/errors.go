@@ -0,0 +1,96 @@
+package core
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	ErrUniqueViolation = errors.New("unique constraint violation")
+	ErrForeignKey      = errors.New("foreign key constraint violation")
+	ErrNotNull         = errors.New("not null constraint violation")
+	ErrCheck           = errors.New("check constraint violation")
+	ErrBusy            = errors.New("database is busy")
+	ErrReadOnly        = errors.New("write operation on read-only db")
+)
+
+// * errors.Is matches the sentinel in Kind, errors.As exposes the failing columns
+type Error struct {
+	Kind    error
+	Table   string
+	Columns []string
+	Code    sqlite3.ErrNoExtended
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func wrapError(err error) error {
+	var sqliteErr sqlite3.Error
+	if err == nil || !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	var kind error
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		kind = ErrUniqueViolation
+	case sqlite3.ErrConstraintForeignKey:
+		kind = ErrForeignKey
+	case sqlite3.ErrConstraintNotNull:
+		kind = ErrNotNull
+	case sqlite3.ErrConstraintCheck:
+		kind = ErrCheck
+	default:
+		switch sqliteErr.Code {
+		case sqlite3.ErrBusy, sqlite3.ErrLocked:
+			kind = ErrBusy
+		case sqlite3.ErrReadonly:
+			kind = ErrReadOnly
+		default:
+			return err
+		}
+	}
+
+	e := &Error{
+		Kind: kind,
+		Code: sqliteErr.ExtendedCode,
+		Err:  err,
+	}
+	if kind == ErrUniqueViolation || kind == ErrNotNull {
+		e.Table, e.Columns = constraintColumns(sqliteErr.Error())
+	}
+	return e
+}
+
+// * "UNIQUE constraint failed: users.email, users.name" -> users, [email name]
+func constraintColumns(message string) (string, []string) {
+	_, list, ok := strings.Cut(message, "constraint failed: ")
+	if !ok {
+		return "", nil
+	}
+
+	var table string
+	var columns []string
+	for _, item := range strings.Split(list, ", ") {
+		t, col, ok := strings.Cut(strings.TrimSpace(item), ".")
+		if !ok {
+			continue
+		}
+		table = t
+		columns = append(columns, col)
+	}
+	return table, columns
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	planTableRegex = regexp.MustCompile(`^(?:SCAN|SEARCH)(?: TABLE)? (\S+)`)
+	planIndexRegex = regexp.MustCompile(`USING (?:(COVERING) )?INDEX (\S+)`)
+)
+
+// * EXPLAIN QUERY PLAN for the SELECT, the builder is left untouched
+func (b *Builder) Explain() (*Plan, error) {
+	query, args, err := b.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := b.queryAutoAsignContext("EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plan := &Plan{}
+	nodes := make(map[int]*PlanNode)
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			return nil, err
+		}
+
+		node := parsePlanNode(id, parent, detail)
+		nodes[id] = node
+		if p, ok := nodes[parent]; ok {
+			p.Children = append(p.Children, node)
+		} else {
+			plan.Nodes = append(plan.Nodes, node)
+		}
+	}
+	return plan, rows.Err()
+}
+
+func parsePlanNode(id, parent int, detail string) *PlanNode {
+	node := &PlanNode{
+		ID:     id,
+		Parent: parent,
+		Detail: detail,
+	}
+
+	if op, _, ok := strings.Cut(detail, " "); ok && (op == "SCAN" || op == "SEARCH") {
+		node.Operation = op
+	}
+
+	if match := planTableRegex.FindStringSubmatch(detail); match != nil {
+		node.Table = match[1]
+	}
+
+	if match := planIndexRegex.FindStringSubmatch(detail); match != nil {
+		node.Covering = match[1] != ""
+		node.Index = match[2]
+	} else if strings.Contains(detail, "USING INTEGER PRIMARY KEY") || strings.Contains(detail, "USING ROWID") {
+		node.Index = "PRIMARY KEY"
+	}
+
+	return node
+}
+
+// * SCAN without an index reads every row of the table
+func (n *PlanNode) FullScan() bool {
+	return n.Operation == "SCAN" && n.Index == ""
+}
+
+func (p *Plan) Walk(fn func(node *PlanNode)) {
+	var walk func(nodes []*PlanNode)
+	walk = func(nodes []*PlanNode) {
+		for _, node := range nodes {
+			fn(node)
+			walk(node.Children)
+		}
+	}
+	walk(p.Nodes)
+}
+
+func (p *Plan) UsesIndex(name string) bool {
+	found := false
+	p.Walk(func(node *PlanNode) {
+		if node.Index == name {
+			found = true
+		}
+	})
+	return found
+}
+
+func (p *Plan) FullScans() []*PlanNode {
+	var scans []*PlanNode
+	p.Walk(func(node *PlanNode) {
+		if node.FullScan() {
+			scans = append(scans, node)
+		}
+	})
+	return scans
+}
+
+func (p *Plan) String() string {
+	var sb strings.Builder
+	var write func(nodes []*PlanNode, depth int)
+	write = func(nodes []*PlanNode, depth int) {
+		for _, node := range nodes {
+			sb.WriteString(fmt.Sprintf("%s%s\n", strings.Repeat("  ", depth), node.Detail))
+			write(node.Children, depth+1)
+		}
+	}
+	write(p.Nodes, 0)
+	return sb.String()
+}
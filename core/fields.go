@@ -0,0 +1,103 @@
+package core
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// * tagged columns match exactly, untagged ones match the field name case-insensitively
+// * order lists every bound field in declaration order, used where column names are unknown
+type structFields struct {
+	exact  map[string][]int
+	folded map[string][]int
+	order  [][]int
+}
+
+var fieldCache sync.Map // reflect.Type -> *structFields
+
+func typeFields(typ reflect.Type) *structFields {
+	if cached, ok := fieldCache.Load(typ); ok {
+		return cached.(*structFields)
+	}
+
+	fields := &structFields{
+		exact:  make(map[string][]int),
+		folded: make(map[string][]int),
+	}
+	fields.walk(typ, nil, "", map[reflect.Type]bool{typ: true})
+
+	cached, _ := fieldCache.LoadOrStore(typ, fields)
+	return cached.(*structFields)
+}
+
+// * embedded structs are flattened, `db:"prefix_"` flattens a struct under a column prefix
+func (f *structFields) walk(typ reflect.Type, index []int, prefix string, seen map[reflect.Type]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+
+		path := append(slices.Clone(index), i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		nested := fieldType.Kind() == reflect.Struct && !isScalar(fieldType) &&
+			((field.Anonymous && tag == "") || strings.HasSuffix(tag, "_"))
+		if nested {
+			// * a nil pointer to an unexported type cannot be allocated
+			if seen[fieldType] || (!field.IsExported() && (!field.Anonymous || field.Type.Kind() == reflect.Pointer)) {
+				continue
+			}
+			seen[fieldType] = true
+			f.walk(fieldType, path, prefix+tag, seen)
+			delete(seen, fieldType)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		f.order = append(f.order, path)
+		if tag != "" {
+			f.add(f.exact, prefix+tag, path)
+		} else {
+			f.add(f.folded, strings.ToLower(prefix+field.Name), path)
+		}
+	}
+}
+
+// * the shallowest field wins, like Go field promotion
+func (f *structFields) add(fields map[string][]int, name string, index []int) {
+	if existing, ok := fields[name]; ok && len(existing) <= len(index) {
+		return
+	}
+	fields[name] = index
+}
+
+func (f *structFields) lookup(column string) ([]int, bool) {
+	if index, ok := f.exact[column]; ok {
+		return index, true
+	}
+	index, ok := f.folded[strings.ToLower(column)]
+	return index, ok
+}
+
+// * nil embedded or prefixed struct pointers are allocated on the way down
+func fieldAddr(val reflect.Value, index []int) any {
+	for i, x := range index {
+		if i > 0 && val.Kind() == reflect.Pointer {
+			if val.IsNil() {
+				val.Set(reflect.New(val.Type().Elem()))
+			}
+			val = val.Elem()
+		}
+		val = val.Field(x)
+	}
+	return val.Addr().Interface()
+}
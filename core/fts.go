@@ -0,0 +1,197 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// * FTS5 requires building with -tags sqlite_fts5
+func (b *Builder) CreateFTS(option FTS) error {
+	if b.TableName == nil {
+		return fmt.Errorf("table name is required")
+	}
+
+	if err := ValidateColumn(*b.TableName); err != nil {
+		return err
+	}
+
+	if len(option.Columns) == 0 {
+		return fmt.Errorf("no columns defined")
+	}
+
+	rowID := "rowid"
+	if option.RowID != "" {
+		if err := ValidateColumn(option.RowID); err != nil {
+			return err
+		}
+		rowID = option.RowID
+	}
+
+	columns := make([]string, len(option.Columns))
+	for i, col := range option.Columns {
+		if err := ValidateColumn(col); err != nil {
+			return err
+		}
+		columns[i] = quote(col)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE VIRTUAL TABLE IF NOT EXISTS ")
+	sb.WriteString(quote(*b.TableName))
+	sb.WriteString(" USING fts5(")
+	sb.WriteString(strings.Join(columns, ", "))
+
+	if option.Content != "" {
+		if err := ValidateColumn(option.Content); err != nil {
+			return err
+		}
+		sb.WriteString(", content=")
+		sb.WriteString(quote(option.Content))
+		sb.WriteString(", content_rowid=")
+		sb.WriteString(quote(rowID))
+	}
+
+	if option.Tokenize != "" {
+		sb.WriteString(", tokenize='")
+		sb.WriteString(strings.ReplaceAll(option.Tokenize, "'", "''"))
+		sb.WriteString("'")
+	}
+	sb.WriteString(")")
+
+	if _, err := b.ExecAutoAsignContext(sb.String()); err != nil {
+		return err
+	}
+
+	if option.Content == "" {
+		return nil
+	}
+
+	for _, query := range ftsTriggers(*b.TableName, option.Content, rowID, option.Columns) {
+		if _, err := b.ExecAutoAsignContext(query); err != nil {
+			return err
+		}
+	}
+
+	// * backfill rows that existed before the index was created
+	_, err := b.ExecAutoAsignContext(fmt.Sprintf("INSERT INTO %s(%s) VALUES ('rebuild')",
+		quote(*b.TableName), quote(*b.TableName)))
+	return err
+}
+
+func ftsTriggers(table, content, rowID string, columns []string) []string {
+	newValues := make([]string, len(columns))
+	oldValues := make([]string, len(columns))
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quote(col)
+		newValues[i] = "new." + quote(col)
+		oldValues[i] = "old." + quote(col)
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s(rowid, %s) VALUES (new.%s, %s);",
+		quote(table), strings.Join(quoted, ", "), quote(rowID), strings.Join(newValues, ", "))
+	remove := fmt.Sprintf("INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.%s, %s);",
+		quote(table), quote(table), strings.Join(quoted, ", "), quote(rowID), strings.Join(oldValues, ", "))
+
+	return []string{
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER INSERT ON %s BEGIN %s END",
+			quote(table+"_ai"), quote(content), insert),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER DELETE ON %s BEGIN %s END",
+			quote(table+"_ad"), quote(content), remove),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER UPDATE ON %s BEGIN %s %s END",
+			quote(table+"_au"), quote(content), remove, insert),
+	}
+}
+
+func (b *Builder) Match(query string, escape ...bool) *Builder {
+	if b.TableName == nil {
+		b.Error = append(b.Error, fmt.Errorf("Match: table name is required"))
+		return b
+	}
+
+	if len(escape) > 0 && escape[0] {
+		query = EscapeFTS(query)
+	}
+
+	if strings.TrimSpace(query) == "" {
+		b.Error = append(b.Error, fmt.Errorf("Match: query is empty"))
+		return b
+	}
+	return b.Where(fmt.Sprintf("%s MATCH ?", quote(*b.TableName)), query)
+}
+
+func (b *Builder) Rank(weights ...float64) *Builder {
+	if b.TableName == nil {
+		b.Error = append(b.Error, fmt.Errorf("Rank: table name is required"))
+		return b
+	}
+	b.OrderByList = append(b.OrderByList, fmt.Sprintf("%s ASC", bm25(*b.TableName, weights)))
+	return b
+}
+
+func (b *Builder) SelectRank(alias string, weights ...float64) *Builder {
+	if b.TableName == nil {
+		b.Error = append(b.Error, fmt.Errorf("SelectRank: table name is required"))
+		return b
+	}
+	if err := ValidateColumn(alias); err != nil {
+		b.Error = append(b.Error, fmt.Errorf("SelectRank: %w", err))
+		return b
+	}
+	b.ExprList = append(b.ExprList, fmt.Sprintf("%s AS %s", bm25(*b.TableName, weights), quote(alias)))
+	return b
+}
+
+func bm25(table string, weights []float64) string {
+	var sb strings.Builder
+	sb.WriteString("bm25(")
+	sb.WriteString(quote(table))
+	for _, w := range weights {
+		sb.WriteString(", ")
+		sb.WriteString(strconv.FormatFloat(w, 'f', -1, 64))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+func (b *Builder) Highlight(column int, open, close, alias string) *Builder {
+	if b.TableName == nil {
+		b.Error = append(b.Error, fmt.Errorf("Highlight: table name is required"))
+		return b
+	}
+	if err := ValidateColumn(alias); err != nil {
+		b.Error = append(b.Error, fmt.Errorf("Highlight: %w", err))
+		return b
+	}
+	b.ExprList = append(b.ExprList, fmt.Sprintf("highlight(%s, %d, %s, %s) AS %s",
+		quote(*b.TableName), column, quoteString(open), quoteString(close), quote(alias)))
+	return b
+}
+
+func (b *Builder) Snippet(column int, open, close, ellipsis string, tokens int, alias string) *Builder {
+	if b.TableName == nil {
+		b.Error = append(b.Error, fmt.Errorf("Snippet: table name is required"))
+		return b
+	}
+	if err := ValidateColumn(alias); err != nil {
+		b.Error = append(b.Error, fmt.Errorf("Snippet: %w", err))
+		return b
+	}
+	if tokens < 1 || tokens > 64 {
+		b.Error = append(b.Error, fmt.Errorf("Snippet: tokens must be between 1 and 64"))
+		return b
+	}
+	b.ExprList = append(b.ExprList, fmt.Sprintf("snippet(%s, %d, %s, %s, %s, %d) AS %s",
+		quote(*b.TableName), column, quoteString(open), quoteString(close), quoteString(ellipsis), tokens, quote(alias)))
+	return b
+}
+
+// * wrap each term as an FTS5 string so operators in user input are literal
+func EscapeFTS(input string) string {
+	terms := strings.Fields(input)
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,125 @@
+//go:build sqlite_fts5 || fts5
+
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuilderFTS(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	NewBuilder(db).Table("posts").Create(
+		Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		Column{Name: "title", Type: "TEXT"},
+		Column{Name: "body", Type: "TEXT"},
+	)
+
+	NewBuilder(db).Table("posts").InsertBatch([]map[string]any{
+		{"title": "sqlite tips", "body": "use WAL mode for concurrency"},
+		{"title": "go tips", "body": "sqlite works well from go"},
+	})
+
+	t.Run("CreateFTS with external content", func(t *testing.T) {
+		err := NewBuilder(db).Table("posts_fts").CreateFTS(FTS{
+			Columns: []string{"title", "body"},
+			Content: "posts",
+			RowID:   "id",
+		})
+		if err != nil {
+			t.Fatalf("create fts failed: %v", err)
+		}
+	})
+
+	t.Run("Match existing rows after rebuild", func(t *testing.T) {
+		count, err := NewBuilder(db).Table("posts_fts").Match("sqlite").Count()
+		if err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2, got %d", count)
+		}
+	})
+
+	t.Run("Triggers sync insert update delete", func(t *testing.T) {
+		id, err := NewBuilder(db).Table("posts").Insert(map[string]any{"title": "rust", "body": "borrow checker"})
+		if err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+
+		count, _ := NewBuilder(db).Table("posts_fts").Match("borrow").Count()
+		if count != 1 {
+			t.Errorf("expected 1 after insert, got %d", count)
+		}
+
+		NewBuilder(db).Table("posts").WhereEq("id", id).Update(map[string]any{"body": "ownership"})
+		count, _ = NewBuilder(db).Table("posts_fts").Match("borrow").Count()
+		if count != 0 {
+			t.Errorf("expected 0 after update, got %d", count)
+		}
+
+		NewBuilder(db).Table("posts").WhereEq("id", id).Delete()
+		count, _ = NewBuilder(db).Table("posts_fts").Match("ownership").Count()
+		if count != 0 {
+			t.Errorf("expected 0 after delete, got %d", count)
+		}
+	})
+
+	t.Run("Rank highlight and snippet", func(t *testing.T) {
+		var results []struct {
+			Title   string  `db:"title"`
+			Marked  string  `db:"marked"`
+			Excerpt string  `db:"excerpt"`
+			Score   float64 `db:"score"`
+		}
+		_, err := NewBuilder(db).Table("posts_fts").
+			Select("title").
+			Highlight(0, "[", "]", "marked").
+			Snippet(1, "<b>", "</b>", "...", 8, "excerpt").
+			SelectRank("score", 10, 1).
+			Match("tips").
+			Rank(10, 1).
+			Bind(&results).
+			Get()
+		if err != nil {
+			t.Fatalf("get failed: %v", err)
+		}
+		if len(results) != 2 {
+			t.Fatalf("expected 2 results, got %d", len(results))
+		}
+		if !strings.Contains(results[0].Marked, "[tips]") {
+			t.Errorf("expected highlighted title, got %q", results[0].Marked)
+		}
+		if results[0].Score > results[1].Score {
+			t.Errorf("expected ascending bm25 order, got %v > %v", results[0].Score, results[1].Score)
+		}
+	})
+
+	t.Run("Match escaped user input", func(t *testing.T) {
+		count, err := NewBuilder(db).Table("posts_fts").Match(`sqlite" OR "go`, true).Count()
+		if err != nil {
+			t.Fatalf("escaped match failed: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("expected 0, got %d", count)
+		}
+	})
+
+	t.Run("Standalone FTS table", func(t *testing.T) {
+		err := NewBuilder(db).Table("notes_fts").CreateFTS(FTS{
+			Columns:  []string{"content"},
+			Tokenize: "porter unicode61",
+		})
+		if err != nil {
+			t.Fatalf("create fts failed: %v", err)
+		}
+
+		NewBuilder(db).Table("notes_fts").Insert(map[string]any{"content": "running quickly"})
+		count, _ := NewBuilder(db).Table("notes_fts").Match("run").Count()
+		if count != 1 {
+			t.Errorf("expected porter stemming match, got %d", count)
+		}
+	})
+}
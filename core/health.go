@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+func (d *Connector) Ping(ctx context.Context) error {
+	if err := d.Read.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping read db: %w", err)
+	}
+	if err := d.Write.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping write db: %w", err)
+	}
+	return nil
+}
+
+func (d *Connector) Stats() (Stats, error) {
+	return d.StatsContext(context.Background())
+}
+
+// * pragmas are read directly from the read pool so polling does not reach query hooks
+func (d *Connector) StatsContext(ctx context.Context) (Stats, error) {
+	stats := Stats{
+		Read:           poolStats(d.Read.DB.Stats()),
+		Write:          poolStats(d.Write.DB.Stats()),
+		Retries:        d.Write.Retry.count(),
+		GroupCommit:    d.Write.Queue.stats(),
+		LastCheckpoint: d.checkpoint.Load(),
+	}
+
+	if err := d.Read.DB.QueryRowContext(ctx, "PRAGMA page_count").Scan(&stats.PageCount); err != nil {
+		return stats, fmt.Errorf("failed to read page_count: %w", err)
+	}
+
+	if err := d.Read.DB.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&stats.FreelistCount); err != nil {
+		return stats, fmt.Errorf("failed to read freelist_count: %w", err)
+	}
+
+	var file string
+	if err := d.Read.DB.QueryRowContext(ctx, "SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&file); err != nil {
+		return stats, fmt.Errorf("failed to read database file: %w", err)
+	}
+
+	// * in-memory databases have no file, a missing WAL means it was truncated
+	if file != "" {
+		info, err := os.Stat(file + "-wal")
+		switch {
+		case err == nil:
+			stats.WALSize = info.Size()
+		case !os.IsNotExist(err):
+			return stats, fmt.Errorf("failed to stat wal file: %w", err)
+		}
+	}
+
+	return stats, nil
+}
+
+func poolStats(s sql.DBStats) PoolStats {
+	return PoolStats{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDuration:       s.WaitDuration,
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	}
+}
+
+// * readiness probe, 200 with stats when both pools answer, 503 otherwise
+func (d *Connector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		body := struct {
+			Status string `json:"status"`
+			Error  string `json:"error,omitempty"`
+			Stats  *Stats `json:"stats,omitempty"`
+		}{Status: "ok"}
+		code := http.StatusOK
+
+		if err := d.Ping(ctx); err != nil {
+			body.Status = "unavailable"
+			body.Error = err.Error()
+			code = http.StatusServiceUnavailable
+		} else if stats, err := d.StatsContext(ctx); err != nil {
+			body.Status = "unavailable"
+			body.Error = err.Error()
+			code = http.StatusServiceUnavailable
+		} else {
+			body.Stats = &stats
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(body)
+	})
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (b *Builder) Use(hooks ...Hook) *Builder {
+	b.Hooks = append(b.Hooks, hooks...)
+	return b
+}
+
+func (d *Connector) Use(hooks ...Hook) {
+	d.Read.Use(hooks...)
+	d.Write.Use(hooks...)
+}
+
+func (b *Builder) beforeQuery(query string, args []any) (context.Context, time.Time) {
+	ctx := b.context()
+	for _, hook := range b.Hooks {
+		ctx = hook.BeforeQuery(ctx, query, args)
+	}
+	return ctx, time.Now()
+}
+
+// * hooks run in reverse so the first registered hook wraps the others
+func (b *Builder) afterQuery(ctx context.Context, query string, args []any, start time.Time, rowsAffected int64, err error) {
+	if len(b.Hooks) == 0 {
+		return
+	}
+
+	duration := time.Since(start)
+	for i := len(b.Hooks) - 1; i >= 0; i-- {
+		b.Hooks[i].AfterQuery(ctx, query, args, duration, rowsAffected, err)
+	}
+}
+
+type LogHook struct {
+	Logger *slog.Logger
+	Slow   time.Duration // 0 disables slow query warnings
+	Redact []string      // columns whose bound values are never logged
+}
+
+func NewLogHook(logger *slog.Logger, slow time.Duration, redact ...string) *LogHook {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LogHook{
+		Logger: logger,
+		Slow:   slow,
+		Redact: redact,
+	}
+}
+
+func (h *LogHook) BeforeQuery(ctx context.Context, query string, args []any) context.Context {
+	return ctx
+}
+
+func (h *LogHook) AfterQuery(ctx context.Context, query string, args []any, duration time.Duration, rowsAffected int64, err error) {
+	attrs := []slog.Attr{
+		slog.String("sql", query),
+		slog.Any("args", h.redact(query, args)),
+		slog.Duration("duration", duration),
+	}
+	if rowsAffected >= 0 {
+		attrs = append(attrs, slog.Int64("rows_affected", rowsAffected))
+	}
+
+	switch {
+	case err != nil:
+		attrs = append(attrs, slog.Any("error", err))
+		h.Logger.LogAttrs(ctx, slog.LevelError, "query failed", attrs...)
+	case h.Slow > 0 && duration >= h.Slow:
+		h.Logger.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+	default:
+		h.Logger.LogAttrs(ctx, slog.LevelDebug, "query", attrs...)
+	}
+}
+
+func (h *LogHook) redact(query string, args []any) []any {
+	if len(h.Redact) == 0 || len(args) == 0 {
+		return args
+	}
+
+	columns := placeholderColumns(query)
+	result := make([]any, len(args))
+	for i, arg := range args {
+		result[i] = arg
+		if i < len(columns) && slices.Contains(h.Redact, columns[i]) {
+			result[i] = "[REDACTED]"
+		}
+	}
+	return result
+}
+
+// * best-effort column for each ? placeholder: INSERT tuples map by position,
+// * otherwise the nearest identifier before the placeholder
+func placeholderColumns(query string) []string {
+	var columns []string
+	var insertColumns []string
+	var last string
+	inValues := false
+	tuple := 0
+
+	upper := strings.ToUpper(strings.TrimSpace(query))
+	if strings.HasPrefix(upper, "INSERT") {
+		if start := strings.Index(query, "("); start >= 0 {
+			if end := strings.Index(query[start:], ")"); end >= 0 {
+				for _, col := range strings.Split(query[start+1:start+end], ",") {
+					insertColumns = append(insertColumns, strings.Trim(strings.TrimSpace(col), `"`))
+				}
+			}
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			end := strings.IndexByte(query[i+1:], '\'')
+			if end < 0 {
+				return columns
+			}
+			i += end + 1
+		case c == '"':
+			end := strings.IndexByte(query[i+1:], '"')
+			if end < 0 {
+				return columns
+			}
+			last = query[i+1 : i+1+end]
+			i += end + 1
+		case c == '?':
+			if inValues && len(insertColumns) > 0 {
+				columns = append(columns, insertColumns[tuple%len(insertColumns)])
+				tuple++
+			} else {
+				columns = append(columns, last)
+			}
+		case isIdentByte(c):
+			start := i
+			for i+1 < len(query) && isIdentByte(query[i+1]) {
+				i++
+			}
+			word := query[start : i+1]
+			switch upperWord := strings.ToUpper(word); {
+			case upperWord == "VALUES" && len(insertColumns) > 0:
+				inValues = true
+			case upperWord == "CONFLICT":
+				inValues = false
+			case !keyMap[upperWord] && columnRegex.MatchString(word):
+				last = word
+			}
+		}
+	}
+	return columns
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 )
@@ -31,6 +32,10 @@ func (b *Builder) Insert(data ...map[string]any) (int64, error) {
 		return 0, err
 	}
 
+	if len(b.ReturningList) > 0 {
+		return b.queryReturning(query, values...)
+	}
+
 	result, err := b.ExecAutoAsignContext(query, values...)
 	if err != nil {
 		return 0, err
@@ -52,7 +57,11 @@ func insertBuilder(b *Builder, data ...map[string]any) (string, []any, error) {
 		return "", nil, err
 	}
 
-	insertData := data[0]
+	scoped, err := b.scopeInsert(data[:1])
+	if err != nil {
+		return "", nil, err
+	}
+	insertData := b.stampInsert(scoped)[0]
 	var conflictData map[string]any
 	if len(data) > 1 {
 		conflictData = data[1]
@@ -77,6 +86,34 @@ func insertBuilder(b *Builder, data ...map[string]any) (string, []any, error) {
 		placeholders = append(placeholders, "?")
 	}
 
+	if len(conflictData) > 0 {
+		for key := range conflictData {
+			if err := ValidateColumn(key); err != nil {
+				return "", nil, err
+			}
+		}
+		b.DoUpdate(conflictData)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(b.buildInsertInto())
+	sb.WriteString(" (")
+	sb.WriteString(strings.Join(columns, ", "))
+	sb.WriteString(") VALUES (")
+	sb.WriteString(strings.Join(placeholders, ", "))
+	sb.WriteString(")")
+
+	onConflict, conflictArgs, err := b.buildUpsert()
+	if err != nil {
+		return "", nil, err
+	}
+	sb.WriteString(onConflict)
+	values = append(values, conflictArgs...)
+
+	return sb.String(), values, nil
+}
+
+func (b *Builder) buildInsertInto() string {
 	var sb strings.Builder
 	sb.WriteString("INSERT")
 	if b.ConflictMode != nil {
@@ -97,33 +134,7 @@ func insertBuilder(b *Builder, data ...map[string]any) (string, []any, error) {
 
 	sb.WriteString(" INTO ")
 	sb.WriteString(quote(*b.TableName))
-	sb.WriteString(" (")
-	sb.WriteString(strings.Join(columns, ", "))
-	sb.WriteString(") VALUES (")
-	sb.WriteString(strings.Join(placeholders, ", "))
-	sb.WriteString(")")
-
-	if conflictData != nil && len(conflictData) > 0 {
-		updateKeys := make([]string, 0, len(conflictData))
-		for key := range conflictData {
-			if err := ValidateColumn(key); err != nil {
-				return "", nil, err
-			}
-			updateKeys = append(updateKeys, key)
-		}
-		sort.Strings(updateKeys)
-
-		setParts := make([]string, 0, len(updateKeys))
-		for _, key := range updateKeys {
-			setParts = append(setParts, fmt.Sprintf("%s = ?", quote(key)))
-			values = append(values, conflictData[key])
-		}
-
-		sb.WriteString(" ON CONFLICT DO UPDATE SET ")
-		sb.WriteString(strings.Join(setParts, ", "))
-	}
-
-	return sb.String(), values, nil
+	return sb.String()
 }
 
 func (b *Builder) InsertBatch(data []map[string]any) (int64, error) {
@@ -137,69 +148,157 @@ func (b *Builder) InsertBatch(data []map[string]any) (int64, error) {
 		return 0, fmt.Errorf("no data to insert")
 	}
 
-	query, values, err := insertBatchBuilder(b, data)
+	queries, values, err := insertBatchBuilder(b, data)
 	if err != nil {
 		return 0, err
 	}
 
-	result, err := b.ExecAutoAsignContext(query, values...)
+	if len(queries) == 1 {
+		if len(b.ReturningList) > 0 {
+			return b.queryReturning(queries[0], values[0]...)
+		}
+
+		result, err := b.ExecAutoAsignContext(queries[0], values[0]...)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	target, err := b.returningTarget()
 	if err != nil {
 		return 0, err
 	}
 
-	return result.RowsAffected()
+	tx, err := b.beginTx(b.context())
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var total int64
+	for i, query := range queries {
+		var affected int64
+		if len(b.ReturningList) > 0 {
+			rows, err := b.txQuery(tx, query+b.buildReturning(), values[i]...)
+			if err != nil {
+				return 0, fmt.Errorf("chunk %d: %w", i, err)
+			}
+			affected, err = bindReturning(rows, target)
+			rows.Close()
+			if err != nil {
+				return 0, fmt.Errorf("chunk %d: %w", i, err)
+			}
+			// * struct target keeps the first returned row only
+			if target.IsValid() && target.Kind() == reflect.Struct {
+				target = reflect.Value{}
+			}
+		} else {
+			result, err := b.txExec(tx, query, values[i]...)
+			if err != nil {
+				return 0, fmt.Errorf("chunk %d: %w", i, err)
+			}
+			if affected, err = result.RowsAffected(); err != nil {
+				return 0, err
+			}
+		}
+		total += affected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, wrapError(err)
+	}
+	return total, nil
 }
 
-func insertBatchBuilder(b *Builder, data []map[string]any) (string, []any, error) {
+// * split rows so each statement stays under maxVariables bound parameters
+func insertBatchBuilder(b *Builder, data []map[string]any) ([]string, [][]any, error) {
 	if b.TableName == nil {
-		return "", nil, fmt.Errorf("table name is required")
+		return nil, nil, fmt.Errorf("table name is required")
 	}
 
 	if len(data) == 0 {
-		return "", nil, fmt.Errorf("no data defined")
+		return nil, nil, fmt.Errorf("no data defined")
 	}
 
 	if err := ValidateColumn(*b.TableName); err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
+	data, err := b.scopeInsert(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	data = b.stampInsert(data)
 	insertData := data[0]
 	keys := make([]string, 0, len(insertData))
 	for key := range insertData {
 		if err := ValidateColumn(key); err != nil {
-			return "", nil, err
+			return nil, nil, err
 		}
 		keys = append(keys, key)
 	}
 	sort.Strings(keys)
 
-	var sb strings.Builder
-	sb.WriteString("INSERT INTO ")
-	sb.WriteString(quote(*b.TableName))
-	sb.WriteString(" (")
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("no columns defined")
+	}
+
+	for i, row := range data[1:] {
+		if len(row) != len(keys) {
+			return nil, nil, fmt.Errorf("row %d: columns do not match first row", i+1)
+		}
+		for _, key := range keys {
+			if _, ok := row[key]; !ok {
+				return nil, nil, fmt.Errorf("row %d: missing column %s", i+1, key)
+			}
+		}
+	}
+
+	onConflict, conflictArgs, err := b.buildUpsert()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := (maxVariables - len(conflictArgs)) / len(keys)
+	if size < 1 {
+		return nil, nil, fmt.Errorf("too many columns for one statement: %d", len(keys))
+	}
 
 	quotedKeys := make([]string, len(keys))
+	placeholders := make([]string, len(keys))
 	for i, key := range keys {
 		quotedKeys[i] = quote(key)
+		placeholders[i] = "?"
 	}
-	sb.WriteString(strings.Join(quotedKeys, ", "))
-	sb.WriteString(") VALUES ")
+	head := fmt.Sprintf("%s (%s) VALUES ", b.buildInsertInto(), strings.Join(quotedKeys, ", "))
+	tuple := "(" + strings.Join(placeholders, ", ") + ")"
 
-	values := make([]any, 0, len(data)*len(keys))
-	for i, row := range data {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
+	queries := make([]string, 0, (len(data)+size-1)/size)
+	values := make([][]any, 0, cap(queries))
+	for start := 0; start < len(data); start += size {
+		end := min(start+size, len(data))
+
+		var sb strings.Builder
+		sb.WriteString(head)
 
-		sb.WriteString("(")
-		placeholders := make([]string, len(keys))
-		for j, key := range keys {
-			placeholders[j] = "?"
-			values = append(values, row[key])
+		chunkValues := make([]any, 0, (end-start)*len(keys)+len(conflictArgs))
+		for i, row := range data[start:end] {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(tuple)
+			for _, key := range keys {
+				chunkValues = append(chunkValues, row[key])
+			}
 		}
-		sb.WriteString(strings.Join(placeholders, ", "))
-		sb.WriteString(")")
+
+		sb.WriteString(onConflict)
+		chunkValues = append(chunkValues, conflictArgs...)
+
+		queries = append(queries, sb.String())
+		values = append(values, chunkValues)
 	}
 
-	return sb.String(), values, nil
+	return queries, values, nil
 }
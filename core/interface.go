@@ -3,40 +3,149 @@ package core
 import (
 	"context"
 	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Config struct {
-	Path         string `json:"path"`
-	Lifetime     int    `json:"lifetime,omitempty"`
-	MaxOpenConns int    `json:"max_read_conns,omitempty"`
-	MaxIdleConns int    `json:"max_idle_conns,omitempty"`
+	Path         string      `json:"path"`
+	Lifetime     int         `json:"lifetime,omitempty"`
+	MaxOpenConns int         `json:"max_read_conns,omitempty"`
+	MaxIdleConns int         `json:"max_idle_conns,omitempty"`
+	CacheSize    int         `json:"cache_size,omitempty"` // prepared statements per pool, 0 disables
+	Retry        RetryPolicy `json:"retry"`                // applied to the write pool
+	GroupCommit  GroupCommit `json:"group_commit"`         // batch small writes into shared transactions
 }
 
 type Connector struct {
-	Read  *Builder
-	Write *Builder
+	Read       *Builder
+	Write      *Builder
+	checkpoint atomic.Pointer[CheckpointResult]
 }
 
-// * Builder is NOT safe for concurrent use by multiple goroutines
+type PoolStats struct {
+	MaxOpenConnections int           `json:"max_open_connections"`
+	OpenConnections    int           `json:"open_connections"`
+	InUse              int           `json:"in_use"`
+	Idle               int           `json:"idle"`
+	WaitCount          int64         `json:"wait_count"`
+	WaitDuration       time.Duration `json:"wait_duration"`
+	MaxIdleClosed      int64         `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64         `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64         `json:"max_lifetime_closed"`
+}
+
+type CheckpointResult struct {
+	Mode         string        `json:"mode"`
+	Busy         bool          `json:"busy"`
+	Log          int64         `json:"log"`          // frames in the WAL
+	Checkpointed int64         `json:"checkpointed"` // frames moved into the database
+	Duration     time.Duration `json:"duration"`
+	Time         time.Time     `json:"time"`
+	Error        string        `json:"error,omitempty"`
+}
+
+type Stats struct {
+	Read           PoolStats         `json:"read"`
+	Write          PoolStats         `json:"write"`
+	WALSize        int64             `json:"wal_size"`
+	PageCount      int64             `json:"page_count"`
+	FreelistCount  int64             `json:"freelist_count"`
+	Retries        int64             `json:"retries"`
+	GroupCommit    GroupCommitStats  `json:"group_commit"`
+	LastCheckpoint *CheckpointResult `json:"last_checkpoint,omitempty"`
+}
+
+// * a single Builder is NOT safe for concurrent use, Table() starts an independent query
+// * so shared Connector.Read / Connector.Write can be used from many goroutines
 type Builder struct {
-	DB           *sql.DB
-	TableName    *string
-	SelectList   []string
-	UpdateList   []string
-	WhereList    []Where
-	WhereArgs    []any
-	JoinList     []Join
-	ConflictMode *conflict
-	OrderByList  []string
-	GroupByList  []string
-	HavingList   []Where
-	HavingArgs   []any
-	WithLimit    *int
-	WithOffset   *int
-	WithTotal    bool
-	WithContext  context.Context
-	WithBind     any
-	Error        []error
+	DB            *sql.DB
+	Cache         *stmtCache
+	Schema        *atomic.Uint64
+	Retry         *retrier
+	Queue         *writeQueue
+	Tables        *tableRegistry
+	Hooks         []Hook
+	TableName     *string
+	SelectList    []string
+	ExprList      []string
+	UpdateList    []string
+	WhereList     []Where
+	WhereArgs     []any
+	JoinList      []Join
+	ConflictMode  *conflict
+	WithUpsert    *upsert
+	ReturningList []string
+	OrderByList   []string
+	GroupByList   []string
+	HavingList    []Where
+	HavingArgs    []any
+	WithLimit     *int
+	WithOffset    *int
+	WithTotal     bool
+	TrashedMode   trashed
+	SkipScopes    []string
+	WithContext   context.Context
+	WithBind      any
+	Error         []error
+}
+
+// * holds the write connection while a batch is open, other writes wait for the commit
+type Loader struct {
+	mu           sync.Mutex
+	builder      *Builder
+	query        string
+	ctx          context.Context
+	stmt         *sql.Stmt
+	tx           *sql.Tx
+	txStmt       *sql.Stmt
+	timer        *time.Timer
+	columns      int
+	fills        []loaderFill
+	conflictArgs []any
+	size         int
+	interval     time.Duration
+	onBatch      func(LoaderBatch)
+	pending      int
+	failed       int
+	start        time.Time
+	batchErr     error
+	closed       bool
+}
+
+type loaderFill struct {
+	index int
+	value func() any
+}
+
+type LoaderBatch struct {
+	Rows          int // rows committed, or rolled back when Error is set
+	Failed        int // rows rejected by Add, not part of Rows
+	Duration      time.Duration
+	RowsPerSecond float64
+	Error         error
+}
+
+// * BeforeQuery may return a derived context, it is passed to the driver and AfterQuery
+type Hook interface {
+	BeforeQuery(ctx context.Context, query string, args []any) context.Context
+	AfterQuery(ctx context.Context, query string, args []any, duration time.Duration, rowsAffected int64, err error)
+}
+
+type Plan struct {
+	Nodes []*PlanNode
+}
+
+type PlanNode struct {
+	ID        int
+	Parent    int
+	Detail    string
+	Operation string // SCAN / SEARCH, empty for other steps
+	Table     string
+	Index     string // "PRIMARY KEY" for rowid lookups
+	Covering  bool
+	Children  []*PlanNode
 }
 
 type Where struct {
@@ -66,6 +175,13 @@ type Foreign struct {
 	Column string
 }
 
+type FTS struct {
+	Columns  []string
+	Content  string // external content table, synced by triggers
+	RowID    string // content table rowid column, default "rowid"
+	Tokenize string
+}
+
 type Union struct {
 	Builder *Builder
 	All     bool
@@ -73,4 +189,15 @@ type Union struct {
 
 type conflict uint32
 
+type upsert struct {
+	Target    []string
+	Nothing   bool
+	SetList   []string
+	SetArgs   []any
+	WhereList []string
+	WhereArgs []any
+}
+
 type direction uint32
+
+type trashed uint32
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// * streams rows into T one at a time, breaking out of the loop closes the rows
+// * a cancelled Context is yielded as the final error
+func Iter[T any](b *Builder) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer builderClear(b)
+
+		var zero T
+		if len(b.Error) > 0 {
+			yield(zero, b.Error[0])
+			return
+		}
+
+		typ := reflect.TypeFor[T]()
+		if typ.Kind() != reflect.Struct {
+			yield(zero, fmt.Errorf("Iter: type must be struct"))
+			return
+		}
+
+		rows, err := get(b)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		cols, err := rows.Columns()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		ctx := b.context()
+		for rows.Next() {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			var item T
+			if err := rows.Scan(scanTarget(reflect.ValueOf(&item).Elem(), typ, cols)...); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// * untyped streaming, a non-nil error from fn stops the iteration and is returned
+func (b *Builder) Each(fn func(row map[string]any) error) error {
+	defer builderClear(b)
+
+	if len(b.Error) > 0 {
+		return b.Error[0]
+	}
+
+	rows, err := get(b)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	cols, blobs, err := mapColumns(rows)
+	if err != nil {
+		return err
+	}
+
+	ctx := b.context()
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		row, err := scanMap(rows, cols, blobs)
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,311 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (b *Builder) Loader(columns ...string) (*Loader, error) {
+	defer builderClear(b)
+
+	if len(b.Error) > 0 {
+		return nil, b.Error[0]
+	}
+
+	if b.TableName == nil {
+		return nil, fmt.Errorf("table name is required")
+	}
+
+	if err := ValidateColumn(*b.TableName); err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns defined")
+	}
+
+	for _, col := range columns {
+		if err := ValidateColumn(col); err != nil {
+			return nil, err
+		}
+	}
+
+	all, fills, err := b.loaderColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	quoted := make([]string, len(all))
+	placeholders := make([]string, len(all))
+	for i, col := range all {
+		quoted[i] = quote(col)
+		placeholders[i] = "?"
+	}
+
+	onConflict, conflictArgs, err := b.buildUpsert()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("%s (%s) VALUES (%s)%s",
+		b.buildInsertInto(), strings.Join(quoted, ", "), strings.Join(placeholders, ", "), onConflict)
+
+	ctx := b.WithContext
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	stmt, err := b.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Loader{
+		builder:      b.session().Context(ctx),
+		query:        query,
+		ctx:          ctx,
+		stmt:         stmt,
+		columns:      len(columns),
+		fills:        fills,
+		conflictArgs: conflictArgs,
+		size:         1000,
+	}, nil
+}
+
+// * scope columns always come from the Loader context, timestamps are filled unless listed
+func (b *Builder) loaderColumns(columns []string) ([]string, []loaderFill, error) {
+	opts, ok := b.tableOptions()
+	if !ok {
+		return columns, nil, nil
+	}
+
+	scopes := b.activeScopes(opts)
+	if err := b.scopeReplace(scopes); err != nil {
+		return nil, nil, err
+	}
+
+	all := slices.Clone(columns)
+	var fills []loaderFill
+	for _, s := range scopes {
+		value, err := s.value(b.context())
+		if err != nil {
+			return nil, nil, err
+		}
+		index := slices.Index(all, s.Column)
+		if index < 0 {
+			all = append(all, s.Column)
+			index = len(all) - 1
+		}
+		fills = append(fills, loaderFill{index: index, value: func() any { return value }})
+	}
+
+	if t := opts.timestamps; t != nil {
+		for _, col := range []string{t.CreatedAt, t.UpdatedAt} {
+			if col == "" || slices.Contains(all, col) {
+				continue
+			}
+			all = append(all, col)
+			fills = append(fills, loaderFill{index: len(all) - 1, value: t.value})
+		}
+	}
+	return all, fills, nil
+}
+
+func (l *Loader) BatchSize(n int) *Loader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if n > 0 {
+		l.size = n
+	}
+	return l
+}
+
+// * commit an open batch after d even if BatchSize is not reached
+func (l *Loader) FlushInterval(d time.Duration) *Loader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.interval = d
+	return l
+}
+
+func (l *Loader) OnBatch(fn func(LoaderBatch)) *Loader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.onBatch = fn
+	return l
+}
+
+func (l *Loader) Add(values ...any) error {
+	if len(values) != l.columns {
+		return fmt.Errorf("expected %d values, got %d", l.columns, len(values))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return fmt.Errorf("loader is closed")
+	}
+
+	if l.tx == nil {
+		tx, err := l.builder.beginTx(l.ctx)
+		if err != nil {
+			return err
+		}
+		l.tx = tx
+		l.txStmt = tx.StmtContext(l.ctx, l.stmt)
+		l.start = time.Now()
+		if l.interval > 0 {
+			l.timer = time.AfterFunc(l.interval, func() {
+				l.Flush()
+			})
+		}
+	}
+
+	args := values
+	if len(l.fills) > 0 || len(l.conflictArgs) > 0 {
+		args = append([]any{}, values...)
+		for _, fill := range l.fills {
+			if fill.index >= len(args) {
+				args = append(args, nil)
+			}
+			args[fill.index] = fill.value()
+		}
+		args = append(args, l.conflictArgs...)
+	}
+
+	// * each row runs in its own savepoint, a failing row never takes the accepted ones with it
+	if _, err := l.tx.ExecContext(l.ctx, "SAVEPOINT loader_row"); err != nil {
+		return l.failBatch(err)
+	}
+
+	ctx, start := l.builder.beforeQuery(l.query, args)
+	result, err := l.txStmt.ExecContext(ctx, args...)
+	err = wrapError(err)
+	affected := int64(-1)
+	if err == nil {
+		affected, _ = result.RowsAffected()
+	}
+	l.builder.afterQuery(ctx, l.query, args, start, affected, err)
+
+	if err != nil {
+		if _, rbErr := l.tx.ExecContext(l.ctx, "ROLLBACK TO loader_row"); rbErr != nil {
+			return l.failBatch(rbErr)
+		}
+	}
+	if _, relErr := l.tx.ExecContext(l.ctx, "RELEASE loader_row"); relErr != nil {
+		return l.failBatch(relErr)
+	}
+	if err != nil {
+		l.failed++
+		return err
+	}
+
+	l.pending++
+	if l.pending >= l.size {
+		return l.flush()
+	}
+	return nil
+}
+
+// * consume rows until ch is closed or the context is done
+// * a failed row is skipped, Load keeps going and returns every failure joined
+func (l *Loader) Load(ch <-chan []any) error {
+	var errs []error
+	for {
+		select {
+		case <-l.ctx.Done():
+			l.Flush()
+			return errors.Join(append(errs, l.ctx.Err())...)
+		case row, ok := <-ch:
+			if !ok {
+				return errors.Join(append(errs, l.Flush())...)
+			}
+			if err := l.Add(row...); err != nil {
+				if l.ctx.Err() != nil {
+					return errors.Join(append(errs, err)...)
+				}
+				errs = append(errs, err)
+			}
+		}
+	}
+}
+
+// * the savepoint state is unknown, the open batch cannot be trusted and is rolled back
+func (l *Loader) failBatch(err error) error {
+	err = wrapError(err)
+	l.batchErr = err
+	rows := l.pending
+	l.flush()
+	return fmt.Errorf("Loader: batch of %d rows rolled back: %w", rows, err)
+}
+
+func (l *Loader) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.flush()
+}
+
+func (l *Loader) flush() error {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+
+	if l.tx == nil {
+		return nil
+	}
+
+	err := l.batchErr
+	if err != nil {
+		l.tx.Rollback()
+	} else {
+		err = wrapError(l.tx.Commit())
+	}
+
+	duration := time.Since(l.start)
+	batch := LoaderBatch{
+		Rows:     l.pending,
+		Failed:   l.failed,
+		Duration: duration,
+		Error:    err,
+	}
+	if err == nil && duration > 0 {
+		batch.RowsPerSecond = float64(l.pending) / duration.Seconds()
+	}
+
+	l.tx = nil
+	l.txStmt = nil
+	l.pending = 0
+	l.failed = 0
+	l.batchErr = nil
+
+	if l.onBatch != nil {
+		l.onBatch(batch)
+	}
+	return err
+}
+
+func (l *Loader) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	err := l.flush()
+	if closeErr := l.stmt.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
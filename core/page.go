@@ -0,0 +1,109 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type Pagination struct {
+	Items   any   `json:"items"` // the Bind target
+	Total   int64 `json:"total"`
+	Page    int   `json:"page"`
+	PerPage int   `json:"per_page"`
+	Pages   int   `json:"pages"`
+	HasNext bool  `json:"has_next"`
+	HasPrev bool  `json:"has_prev"`
+}
+
+// * OFFSET pagination, page starts at 1, rows are bound into the Bind slice
+func (b *Builder) Page(page, perPage int) (*Pagination, error) {
+	defer builderClear(b)
+
+	if len(b.Error) > 0 {
+		return nil, b.Error[0]
+	}
+
+	if page < 1 || perPage < 1 {
+		return nil, fmt.Errorf("Page: page and perPage must be positive")
+	}
+
+	if b.WithLimit != nil || b.WithOffset != nil {
+		return nil, fmt.Errorf("Page: cannot combine with Limit / Offset")
+	}
+
+	targetVal := reflect.ValueOf(b.WithBind)
+	if targetVal.Kind() != reflect.Pointer || targetVal.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Page: Bind target must be a pointer to slice")
+	}
+	sliceVal := targetVal.Elem()
+	elemType := sliceVal.Type().Elem()
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Page: Bind target must be a slice of struct")
+	}
+
+	// * an empty page carries no total column, count on the untouched query instead
+	countBuilder := b.Clone()
+
+	b.WithTotal = true
+	b.Limit(perPage)
+	b.Offset((page - 1) * perPage)
+
+	rows, err := get(b)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	items := reflect.MakeSlice(sliceVal.Type(), 0, perPage)
+	for rows.Next() {
+		item := reflect.New(elemType).Elem()
+		dest := append([]any{&total}, scanTarget(item, elemType, cols[1:])...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		items = reflect.Append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sliceVal.Set(items)
+
+	if items.Len() == 0 && page > 1 {
+		if total, err = countBuilder.countAll(); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Pagination{
+		Items:   b.WithBind,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+		Pages:   int((total + int64(perPage) - 1) / int64(perPage)),
+		HasPrev: page > 1,
+	}
+	result.HasNext = page < result.Pages
+	return result, nil
+}
+
+// * counts result rows rather than table rows, so GROUP BY counts groups
+func (b *Builder) countAll() (int64, error) {
+	defer builderClear(b)
+
+	b.WithTotal = false
+	query, err := selectBuilder(b, false)
+	if err != nil {
+		return 0, err
+	}
+
+	args := append(b.whereArgs(), b.HavingArgs...)
+	var count int64
+	err = b.queryRowAutoAsignContext("SELECT COUNT(*) FROM ("+query+")", args...).Scan(&count)
+	return count, wrapError(err)
+}
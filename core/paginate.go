@@ -0,0 +1,261 @@
+package core
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// * opaque cursors, empty when there is no page in that direction
+type Cursor struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+type cursorState struct {
+	Backward bool  `json:"b,omitempty"`
+	Values   []any `json:"v"`
+}
+
+type orderKey struct {
+	column string
+	desc   bool
+}
+
+// * keyset pagination over the OrderBy columns, the last one must be unique (e.g. id)
+// * columns must be selected and NOT NULL, the page is bound into the Bind slice
+func (b *Builder) Paginate(cursor string, size int) (*Cursor, error) {
+	defer builderClear(b)
+
+	if len(b.Error) > 0 {
+		return nil, b.Error[0]
+	}
+
+	if size < 1 {
+		return nil, fmt.Errorf("Paginate: size must be positive")
+	}
+
+	if b.WithTotal || b.WithLimit != nil || b.WithOffset != nil {
+		return nil, fmt.Errorf("Paginate: cannot combine with Total / Limit / Offset")
+	}
+
+	targetVal := reflect.ValueOf(b.WithBind)
+	if targetVal.Kind() != reflect.Pointer || targetVal.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Paginate: Bind target must be a pointer to slice")
+	}
+	sliceVal := targetVal.Elem()
+	if sliceVal.Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Paginate: Bind target must be a slice of struct")
+	}
+
+	keys, err := b.orderKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	state := cursorState{}
+	if cursor != "" {
+		if state, err = decodeCursor(cursor, len(keys)); err != nil {
+			return nil, err
+		}
+		b.seek(keys, state)
+	}
+
+	// * walking backward reverses the order, rows are flipped back after scanning
+	if state.Backward {
+		b.OrderByList = b.OrderByList[:0]
+		for _, key := range keys {
+			b.OrderByList = append(b.OrderByList, orderClause(key.column, !key.desc))
+		}
+	}
+	b.Limit(size + 1)
+
+	rows, err := get(b)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]int, len(keys))
+	for i, key := range keys {
+		if positions[i] = slices.Index(cols, key.column); positions[i] < 0 {
+			return nil, fmt.Errorf("Paginate: order column %s must be selected", key.column)
+		}
+	}
+
+	elemType := sliceVal.Type().Elem()
+	items := reflect.MakeSlice(sliceVal.Type(), 0, size)
+	boundaries := make([][]any, 0, size+1)
+	for rows.Next() {
+		item := reflect.New(elemType).Elem()
+		dest := scanTarget(item, elemType, cols)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		values := make([]any, len(keys))
+		for i, pos := range positions {
+			values[i] = cursorValue(reflect.ValueOf(dest[pos]).Elem().Interface())
+		}
+		boundaries = append(boundaries, values)
+		items = reflect.Append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	more := items.Len() > size
+	if more {
+		items = items.Slice(0, size)
+		boundaries = boundaries[:size]
+	}
+
+	if state.Backward {
+		swap := reflect.Swapper(items.Interface())
+		for i, j := 0, items.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+		slices.Reverse(boundaries)
+	}
+	sliceVal.Set(items)
+
+	result := &Cursor{}
+	if len(boundaries) == 0 {
+		return result, nil
+	}
+
+	// * forward: more rows means a next page, any cursor means a previous one, mirrored backward
+	hasNext := (!state.Backward && more) || (state.Backward && cursor != "")
+	hasPrev := (state.Backward && more) || (!state.Backward && cursor != "")
+
+	if hasNext {
+		if result.Next, err = encodeCursor(cursorState{Values: boundaries[len(boundaries)-1]}); err != nil {
+			return nil, err
+		}
+	}
+	if hasPrev {
+		if result.Prev, err = encodeCursor(cursorState{Backward: true, Values: boundaries[0]}); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// * reverse of OrderBy, only plain "column" ASC / DESC entries can be sought
+func (b *Builder) orderKeys() ([]orderKey, error) {
+	if len(b.OrderByList) == 0 {
+		return nil, fmt.Errorf("Paginate: OrderBy is required")
+	}
+
+	keys := make([]orderKey, 0, len(b.OrderByList))
+	for _, e := range b.OrderByList {
+		column, dir, ok := strings.Cut(e, " ")
+		name := strings.Trim(column, `"`)
+		if !ok || len(name) != len(column)-2 || ValidateColumn(name) != nil {
+			return nil, fmt.Errorf("Paginate: unsupported ORDER BY %s", e)
+		}
+		keys = append(keys, orderKey{column: name, desc: dir == "DESC"})
+	}
+	return keys, nil
+}
+
+func orderClause(column string, desc bool) string {
+	if desc {
+		return fmt.Sprintf("%s DESC", quote(column))
+	}
+	return fmt.Sprintf("%s ASC", quote(column))
+}
+
+// * (a > ?) OR (a = ? AND b > ?) ... works for mixed directions
+func (b *Builder) seek(keys []orderKey, state cursorState) {
+	var branches []string
+	var args []any
+
+	for i, key := range keys {
+		parts := make([]string, 0, i+1)
+		for j := range i {
+			parts = append(parts, fmt.Sprintf("%s = ?", quote(keys[j].column)))
+			args = append(args, state.Values[j])
+		}
+
+		op := ">"
+		if key.desc != state.Backward {
+			op = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s ?", quote(key.column), op))
+		args = append(args, state.Values[i])
+
+		branches = append(branches, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	b.groupWhere()
+	b.Where("("+strings.Join(branches, " OR ")+")", args...)
+}
+
+// * time values use the driver's storage format so the comparison stays textual
+func cursorValue(v any) any {
+	if val := reflect.ValueOf(v); val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return nil
+		}
+		v = val.Elem().Interface()
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		inner, err := valuer.Value()
+		if err == nil {
+			v = inner
+		}
+	}
+	if t, ok := v.(time.Time); ok {
+		return t.Format(sqlite3.SQLiteTimestampFormats[0])
+	}
+	return v
+}
+
+func encodeCursor(state cursorState) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(cursor string, size int) (cursorState, error) {
+	var state cursorState
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return state, fmt.Errorf("Paginate: invalid cursor")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&state); err != nil || len(state.Values) != size {
+		return state, fmt.Errorf("Paginate: invalid cursor")
+	}
+
+	// * keep integers exact, json.Number would otherwise bind as text
+	for i, v := range state.Values {
+		if n, ok := v.(json.Number); ok {
+			if integer, err := n.Int64(); err == nil {
+				state.Values[i] = integer
+			} else if float, err := n.Float64(); err == nil {
+				state.Values[i] = float
+			}
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// * compiled statement, safe to execute repeatedly and from many goroutines
+type Query struct {
+	SQL     string
+	Args    []any
+	builder *Builder
+	scopes  []scopeArg
+}
+
+// * scope placeholders are re-read from the execution context on every run
+type scopeArg struct {
+	index int
+	scope Scope
+}
+
+func (b *Builder) ToSQL() (string, []any, error) {
+	if len(b.Error) > 0 {
+		return "", nil, b.Error[0]
+	}
+
+	query, err := selectBuilder(b, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	args := append(b.whereArgs(), b.HavingArgs...)
+	return query, args, nil
+}
+
+func (b *Builder) CountSQL() (string, []any, error) {
+	if len(b.Error) > 0 {
+		return "", nil, b.Error[0]
+	}
+
+	query, err := selectBuilder(b, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	args := append(b.whereArgs(), b.HavingArgs...)
+	return query, args, nil
+}
+
+// * statement variants work on a clone, the builder can still run the real call
+func (b *Builder) InsertSQL(data ...map[string]any) (string, []any, error) {
+	if len(b.Error) > 0 {
+		return "", nil, b.Error[0]
+	}
+
+	next := b.Clone()
+	query, args, err := insertBuilder(next, data...)
+	if err != nil {
+		return "", nil, err
+	}
+	return query + next.buildReturning(), args, nil
+}
+
+// * chunks are joined with ";" when the batch exceeds the variable limit
+func (b *Builder) InsertBatchSQL(data []map[string]any) (string, []any, error) {
+	if len(b.Error) > 0 {
+		return "", nil, b.Error[0]
+	}
+
+	queries, values, err := insertBatchBuilder(b.Clone(), data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	args := make([]any, 0)
+	for i := range queries {
+		queries[i] += b.buildReturning()
+		args = append(args, values[i]...)
+	}
+	return strings.Join(queries, ";\n"), args, nil
+}
+
+func (b *Builder) UpdateSQL(data ...map[string]any) (string, []any, error) {
+	if len(b.Error) > 0 {
+		return "", nil, b.Error[0]
+	}
+
+	query, args, err := updateBuilder(b.Clone(), data...)
+	if err != nil {
+		return "", nil, err
+	}
+	return query + b.buildReturning(), args, nil
+}
+
+func (b *Builder) DeleteSQL(force ...bool) (string, []any, error) {
+	if len(b.Error) > 0 {
+		return "", nil, b.Error[0]
+	}
+
+	query, args, err := deleteBuilder(b.Clone(), force...)
+	if err != nil {
+		return "", nil, err
+	}
+	return query + b.buildReturning(), args, nil
+}
+
+// * SELECT with args rendered as SQL literals, for logs only
+func (b *Builder) Interpolated() (string, error) {
+	query, args, err := b.ToSQL()
+	if err != nil {
+		return "", err
+	}
+	return Interpolate(query, args), nil
+}
+
+func (b *Builder) Compile() (*Query, error) {
+	query, args, err := b.ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{
+		SQL:     query,
+		Args:    args,
+		builder: b.session().Context(b.WithContext),
+	}
+	if opts, ok := b.tableOptions(); ok {
+		for i, s := range b.activeScopes(opts) {
+			q.scopes = append(q.scopes, scopeArg{index: len(b.WhereArgs) + i, scope: s})
+		}
+	}
+	return q, nil
+}
+
+func (q *Query) Context(ctx context.Context) *Query {
+	next := *q
+	next.builder = q.builder.session().Context(ctx)
+	return &next
+}
+
+// * no args reuses the compiled ones, otherwise every placeholder must be given
+func (q *Query) args(args []any) ([]any, error) {
+	if len(args) == 0 {
+		args = q.Args
+	} else if len(args) != len(q.Args) {
+		return nil, fmt.Errorf("expected %d args, got %d", len(q.Args), len(args))
+	}
+
+	if len(q.scopes) == 0 {
+		return args, nil
+	}
+
+	args = slices.Clone(args)
+	for _, s := range q.scopes {
+		value, err := s.scope.value(q.builder.context())
+		if err != nil {
+			return nil, err
+		}
+		args[s.index] = value
+	}
+	return args, nil
+}
+
+func (q *Query) Get(args ...any) (*sql.Rows, error) {
+	args, err := q.args(args)
+	if err != nil {
+		return nil, err
+	}
+	return q.builder.queryAutoAsignContext(q.SQL, args...)
+}
+
+func (q *Query) Row(args ...any) (*sql.Row, error) {
+	args, err := q.args(args)
+	if err != nil {
+		return nil, err
+	}
+	return q.builder.queryRowAutoAsignContext(q.SQL, args...), nil
+}
+
+func (q *Query) Exec(args ...any) (sql.Result, error) {
+	args, err := q.args(args)
+	if err != nil {
+		return nil, err
+	}
+	return q.builder.ExecAutoAsignContext(q.SQL, args...)
+}
+
+func (q *Query) Bind(target any, args ...any) error {
+	targetElem, err := bindTarget(target)
+	if err != nil {
+		return err
+	}
+
+	rows, err := q.Get(args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if targetElem.Kind() == reflect.Slice && !isScalar(targetElem.Type()) {
+		targetElem.Set(reflect.MakeSlice(targetElem.Type(), 0, 0))
+	}
+	return bindRows(rows, targetElem)
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxAttempts int           `json:"max_attempts,omitempty"` // total attempts, 0 or 1 disables retries
+	BaseDelay   time.Duration `json:"base_delay,omitempty"`   // default 10ms
+	MaxDelay    time.Duration `json:"max_delay,omitempty"`    // default 1s
+}
+
+type retrier struct {
+	policy  RetryPolicy
+	retries atomic.Int64
+}
+
+func newRetrier(p RetryPolicy) *retrier {
+	if p.MaxAttempts <= 1 {
+		return nil
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = 10 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = time.Second
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	return &retrier{policy: p}
+}
+
+// * re-run fn while it fails with ErrBusy, a nil retrier runs fn once
+func (r *retrier) do(ctx context.Context, fn func() error) error {
+	err := fn()
+	if r == nil {
+		return err
+	}
+
+	for attempt := 1; attempt < r.policy.MaxAttempts && errors.Is(err, ErrBusy); attempt++ {
+		timer := time.NewTimer(r.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		r.retries.Add(1)
+		err = fn()
+	}
+	return err
+}
+
+// * exponential backoff capped at MaxDelay, jittered into [delay/2, delay]
+func (r *retrier) backoff(attempt int) time.Duration {
+	delay := r.policy.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > r.policy.MaxDelay {
+		delay = r.policy.MaxDelay
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+func (r *retrier) count() int64 {
+	if r == nil {
+		return 0
+	}
+	return r.retries.Load()
+}
+
+// * BEGIN IMMEDIATE takes the write lock up front, so only the begin is retried
+func (b *Builder) beginTx(ctx context.Context) (*sql.Tx, error) {
+	var tx *sql.Tx
+	err := b.Retry.do(ctx, func() error {
+		var err error
+		tx, err = b.DB.BeginTx(ctx, nil)
+		return wrapError(err)
+	})
+	return tx, err
+}
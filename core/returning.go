@@ -0,0 +1,80 @@
+package core
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// * requires SQLite 3.35+, turns Insert / InsertBatch / Update / Delete into queries
+func (b *Builder) Returning(columns ...string) *Builder {
+	for _, col := range columns {
+		if col == "*" {
+			b.ReturningList = append(b.ReturningList, col)
+			continue
+		}
+		if err := ValidateColumn(col); err != nil {
+			b.Error = append(b.Error, fmt.Errorf("Returning: %w", err))
+			return b
+		}
+		b.ReturningList = append(b.ReturningList, quote(col))
+	}
+	return b
+}
+
+func (b *Builder) buildReturning() string {
+	if len(b.ReturningList) == 0 {
+		return ""
+	}
+	return " RETURNING " + strings.Join(b.ReturningList, ", ")
+}
+
+// * returns the number of rows produced by RETURNING, bound into WithBind when set
+func (b *Builder) queryReturning(query string, args ...any) (int64, error) {
+	target, err := b.returningTarget()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := b.queryAutoAsignContext(query+b.buildReturning(), args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	return bindReturning(rows, target)
+}
+
+func (b *Builder) returningTarget() (reflect.Value, error) {
+	if b.WithBind == nil {
+		return reflect.Value{}, nil
+	}
+
+	return bindTarget(b.WithBind)
+}
+
+// * an invalid target only counts rows
+func bindReturning(rows *sql.Rows, target reflect.Value) (int64, error) {
+	if !target.IsValid() {
+		var count int64
+		for rows.Next() {
+			count++
+		}
+		return count, rows.Err()
+	}
+
+	if target.Kind() == reflect.Slice && !isScalar(target.Type()) {
+		before := target.Len()
+		if err := bindRows(rows, target); err != nil {
+			return 0, err
+		}
+		return int64(target.Len() - before), nil
+	}
+
+	if err := bindRows(rows, target); err != nil {
+		return 0, err
+	}
+	count, err := bindReturning(rows, reflect.Value{})
+	return count + 1, err
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrScopeMissing = errors.New("scope value missing from context")
+	ErrScopeReplace = errors.New("Conflict(Replace) is not allowed on a scoped table")
+)
+
+// * adds "table"."Column" = Value(ctx) to reads, updates and deletes, and fills Column on insert
+type Scope struct {
+	Name   string
+	Column string
+	Value  func(ctx context.Context) (any, bool)
+}
+
+// * reads the scope value from ctx.Value(key), e.g. a tenant id set by middleware
+func TenantScope(column string, key any) Scope {
+	return Scope{
+		Name:   "tenant",
+		Column: column,
+		Value: func(ctx context.Context) (any, bool) {
+			value := ctx.Value(key)
+			return value, value != nil
+		},
+	}
+}
+
+func (d *Connector) Scope(s Scope, tables ...string) error {
+	if s.Name == "" {
+		return fmt.Errorf("scope name is required")
+	}
+	if s.Value == nil {
+		return fmt.Errorf("scope %s: value is required", s.Name)
+	}
+	if err := ValidateColumn(s.Column); err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("scope %s: no tables defined", s.Name)
+	}
+	for _, table := range tables {
+		if err := ValidateColumn(table); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range tables {
+		d.Write.Tables.update(table, func(opts *tableOptions) {
+			opts.scopes = append(slices.Clip(opts.scopes), s)
+		})
+	}
+	return nil
+}
+
+// * bypass the named scopes, or every scope when called without names
+func (b *Builder) WithoutScopes(names ...string) *Builder {
+	if len(names) == 0 {
+		names = []string{"*"}
+	}
+	b.SkipScopes = append(b.SkipScopes, names...)
+	return b
+}
+
+func (b *Builder) activeScopes(opts tableOptions) []Scope {
+	if slices.Contains(b.SkipScopes, "*") {
+		return nil
+	}
+
+	active := make([]Scope, 0, len(opts.scopes))
+	for _, s := range opts.scopes {
+		if !slices.Contains(b.SkipScopes, s.Name) {
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+// * REPLACE deletes the conflicting row before inserting, whichever scope it belongs to
+func (b *Builder) scopeReplace(scopes []Scope) error {
+	if len(scopes) > 0 && b.ConflictMode != nil && *b.ConflictMode == Replace {
+		return fmt.Errorf("%w: %s", ErrScopeReplace, *b.TableName)
+	}
+	return nil
+}
+
+// * a missing value fails the query instead of silently widening it
+func (s Scope) value(ctx context.Context) (any, error) {
+	value, ok := s.Value(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrScopeMissing, s.Name)
+	}
+	return value, nil
+}
+
+// * the scope column is always taken from the context, a caller value is overwritten
+func (b *Builder) scopeInsert(data []map[string]any) ([]map[string]any, error) {
+	opts, ok := b.tableOptions()
+	if !ok {
+		return data, nil
+	}
+
+	scopes := b.activeScopes(opts)
+	if len(scopes) == 0 {
+		return data, nil
+	}
+	if err := b.scopeReplace(scopes); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]any, len(data))
+	for i, row := range data {
+		result[i] = make(map[string]any, len(row)+len(scopes))
+		for k, v := range row {
+			result[i][k] = v
+		}
+	}
+	for _, s := range scopes {
+		value, err := s.value(b.context())
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range result {
+			row[s.Column] = value
+		}
+	}
+	return result, nil
+}
+
+// * appended last to UPDATE / DO UPDATE SET, SQLite keeps the rightmost assignment of a column,
+// * so a row can never be moved to another scope value
+func (b *Builder) scopeAssignments() ([]string, []any, error) {
+	opts, ok := b.tableOptions()
+	if !ok {
+		return nil, nil, nil
+	}
+
+	var assignments []string
+	var args []any
+	for _, s := range b.activeScopes(opts) {
+		value, err := s.value(b.context())
+		if err != nil {
+			return nil, nil, err
+		}
+		assignments = append(assignments, fmt.Sprintf("%s = ?", quote(s.Column)))
+		args = append(args, value)
+	}
+	return assignments, args, nil
+}
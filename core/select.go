@@ -176,6 +176,10 @@ func selectBuilder(b *Builder, count bool) (string, error) {
 		sb.WriteString("COUNT(*)")
 	} else if len(b.SelectList) == 0 {
 		sb.WriteString("*")
+		for _, e := range b.ExprList {
+			sb.WriteString(", ")
+			sb.WriteString(e)
+		}
 	} else {
 		cols := make([]string, len(b.SelectList))
 		for i, col := range b.SelectList {
@@ -188,6 +192,7 @@ func selectBuilder(b *Builder, count bool) (string, error) {
 				cols[i] = quote(col)
 			}
 		}
+		cols = append(cols, b.ExprList...)
 		sb.WriteString(strings.Join(cols, ", "))
 	}
 
@@ -200,7 +205,10 @@ func selectBuilder(b *Builder, count bool) (string, error) {
 	}
 	sb.WriteString(query)
 
-	where := b.buildWhere()
+	where, err := b.buildWhere()
+	if err != nil {
+		return "", err
+	}
 	groupBy := b.buildGroupBy()
 	having := b.buildHaving()
 	orderBy := b.buildOrderBy()
@@ -238,20 +246,14 @@ func selectBuilder(b *Builder, count bool) (string, error) {
 func (b *Builder) Get() (*sql.Rows, error) {
 	defer builderClear(b)
 
-	var targetVal reflect.Value
-	var targetElem reflect.Value
+	var target reflect.Value
 	if b.WithBind != nil {
-		targetVal = reflect.ValueOf(b.WithBind)
-		if targetVal.Kind() != reflect.Pointer {
-			return nil, fmt.Errorf("target must be a pointer")
+		var err error
+		if target, err = bindTarget(b.WithBind); err != nil {
+			return nil, err
 		}
-
-		targetElem = targetVal.Elem()
-		switch targetElem.Kind() {
-		case reflect.Struct:
+		if target.Kind() != reflect.Slice || isScalar(target.Type()) {
 			b.Limit(1)
-		default:
-			break
 		}
 	}
 
@@ -259,17 +261,10 @@ func (b *Builder) Get() (*sql.Rows, error) {
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
 	if b.WithBind != nil {
-		switch targetElem.Kind() {
-		case reflect.Slice:
-			return rows, findSlice(rows, targetElem)
-		case reflect.Struct:
-			return rows, find(rows, targetElem)
-		default:
-			return rows, fmt.Errorf("target must br struct or slice")
-		}
+		defer rows.Close()
+		return rows, bindRows(rows, target)
 	}
 	return rows, err
 }
@@ -280,11 +275,8 @@ func get(b *Builder) (*sql.Rows, error) {
 		return nil, err
 	}
 
-	args := append(b.WhereArgs, b.HavingArgs...)
-	if b.WithContext != nil {
-		return b.DB.QueryContext(b.WithContext, query, args...)
-	}
-	return b.DB.Query(query, args...)
+	args := append(b.whereArgs(), b.HavingArgs...)
+	return b.queryAutoAsignContext(query, args...)
 }
 
 func findSlice(rows *sql.Rows, sliceVal reflect.Value) error {
@@ -325,53 +317,18 @@ func find(rows *sql.Rows, structVal reflect.Value) error {
 	return rows.Scan(scanTarget...)
 }
 
-func findRow(row *sql.Row, structVal reflect.Value) error {
-	structType := structVal.Type()
-	scanDest := make([]any, structType.NumField())
-
-	fieldMap := make(map[string]int)
-	for i := 0; i < structType.NumField(); i++ {
-		field := structType.Field(i)
-		dbTag := field.Tag.Get("db")
-		if dbTag != "" {
-			fieldMap[dbTag] = i
-		} else {
-			fieldMap[strings.ToLower(field.Name)] = i
-		}
-	}
-
-	for i := 0; i < structType.NumField(); i++ {
-		scanDest[i] = structVal.Field(i).Addr().Interface()
-	}
-
-	return row.Scan(scanDest...)
-}
-
 func scanTarget(val reflect.Value, typ reflect.Type, cols []string) []any {
+	fields := typeFields(typ)
 	scanTarget := make([]any, len(cols))
 
-	var dummy any
 	for i, col := range cols {
-		pattern := getPattern(val, typ, col)
-		if pattern == nil {
-			scanTarget[i] = &dummy
+		if index, ok := fields.lookup(col); ok {
+			scanTarget[i] = fieldAddr(val, index)
 		} else {
-			scanTarget[i] = pattern
+			// * a holder per column keeps unmapped values readable, e.g. pagination keys
+			scanTarget[i] = new(any)
 		}
 	}
 
 	return scanTarget
 }
-
-func getPattern(val reflect.Value, typ reflect.Type, colName string) any {
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		dbTag := field.Tag.Get("db")
-
-		if dbTag == colName || (dbTag == "" && strings.EqualFold(field.Name, colName)) {
-			return val.Field(i).Addr().Interface()
-		}
-	}
-
-	return nil
-}
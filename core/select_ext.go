@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
-	"strings"
 )
 
 func (b *Builder) First() (*sql.Row, error) {
@@ -12,14 +11,6 @@ func (b *Builder) First() (*sql.Row, error) {
 
 	if len(b.OrderByList) == 0 {
 		b.OrderByList = []string{"ROWID DESC"}
-	} else {
-		for i, order := range b.OrderByList {
-			if strings.Contains(order, " ASC") {
-				b.OrderByList[i] = strings.Replace(order, " ASC", " DESC", 1)
-			} else if strings.Contains(order, " DESC") {
-				b.OrderByList[i] = strings.Replace(order, " DESC", " ASC", 1)
-			}
-		}
 	}
 
 	b.Limit(1)
@@ -33,28 +24,8 @@ func (b *Builder) First() (*sql.Row, error) {
 		return nil, err
 	}
 
-	args := append(b.WhereArgs, b.HavingArgs...)
-	var row *sql.Row
-	if b.WithContext != nil {
-		row = b.DB.QueryRowContext(b.WithContext, query, args...)
-	} else {
-		row = b.DB.QueryRow(query, args...)
-	}
-
-	if b.WithBind != nil {
-		targetVal := reflect.ValueOf(b.WithBind)
-		if targetVal.Kind() != reflect.Pointer {
-			return row, fmt.Errorf("target must be a pointer")
-		}
-
-		targetElem := targetVal.Elem()
-		if targetElem.Kind() != reflect.Struct {
-			return row, fmt.Errorf("target must be struct")
-		}
-
-		return row, findRow(row, targetElem)
-	}
-	return row, nil
+	args := append(b.whereArgs(), b.HavingArgs...)
+	return b.firstRow(query, args...)
 }
 
 func (b *Builder) Last() (*sql.Row, error) {
@@ -71,28 +42,39 @@ func (b *Builder) Last() (*sql.Row, error) {
 		return nil, err
 	}
 
-	args := append(b.WhereArgs, b.HavingArgs...)
-	var row *sql.Row
-	if b.WithContext != nil {
-		row = b.DB.QueryRowContext(b.WithContext, query, args...)
-	} else {
-		row = b.DB.QueryRow(query, args...)
+	args := append(b.whereArgs(), b.HavingArgs...)
+	return b.firstRow(query, args...)
+}
+
+// * *sql.Row exposes no column names, a struct is scanned in field declaration order
+func (b *Builder) firstRow(query string, args ...any) (*sql.Row, error) {
+	row := b.queryRowAutoAsignContext(query, args...)
+	if b.WithBind == nil {
+		return row, nil
 	}
 
-	if b.WithBind != nil {
-		targetVal := reflect.ValueOf(b.WithBind)
-		if targetVal.Kind() != reflect.Pointer {
-			return row, fmt.Errorf("target must be a pointer")
-		}
+	target, err := bindTarget(b.WithBind)
+	if err != nil {
+		return row, err
+	}
 
-		targetElem := targetVal.Elem()
-		if targetElem.Kind() != reflect.Struct {
-			return row, fmt.Errorf("target must be struct")
-		}
+	switch {
+	case isScalar(target.Type()):
+		return row, wrapError(row.Scan(target.Addr().Interface()))
+	case target.Kind() == reflect.Struct:
+		return row, wrapError(findRow(row, target))
+	default:
+		return row, fmt.Errorf("target must be struct or scalar, use Get for maps and slices")
+	}
+}
 
-		return row, findRow(row, targetElem)
+func findRow(row *sql.Row, structVal reflect.Value) error {
+	fields := typeFields(structVal.Type())
+	scanDest := make([]any, len(fields.order))
+	for i, index := range fields.order {
+		scanDest[i] = fieldAddr(structVal, index)
 	}
-	return row, nil
+	return row.Scan(scanDest...)
 }
 
 func (b *Builder) Count() (int64, error) {
@@ -107,12 +89,8 @@ func (b *Builder) Count() (int64, error) {
 		return 0, err
 	}
 
-	args := append(b.WhereArgs, b.HavingArgs...)
+	args := append(b.whereArgs(), b.HavingArgs...)
 	var count int64
-	if b.WithContext != nil {
-		err = b.DB.QueryRowContext(b.WithContext, query, args...).Scan(&count)
-	} else {
-		err = b.DB.QueryRow(query, args...).Scan(&count)
-	}
-	return count, err
+	err = b.queryRowAutoAsignContext(query, args...).Scan(&count)
+	return count, wrapError(err)
 }
@@ -5,14 +5,16 @@ import (
 	"strings"
 )
 
-func (b *Builder) buildWhere() string {
-	if len(b.WhereList) == 0 {
-		return ""
+func (b *Builder) buildWhere() (string, error) {
+	scopes, _, err := b.scopes()
+	if err != nil {
+		return "", err
+	}
+	if len(b.WhereList) == 0 && len(scopes) == 0 {
+		return "", nil
 	}
 
 	var sb strings.Builder
-	sb.WriteString(" WHERE ")
-
 	for i, e := range b.WhereList {
 		if i > 0 {
 			sb.WriteString(" ")
@@ -22,7 +24,36 @@ func (b *Builder) buildWhere() string {
 		sb.WriteString(e.Condition)
 	}
 
-	return sb.String()
+	if len(scopes) == 0 {
+		return " WHERE " + sb.String(), nil
+	}
+
+	// * group user conditions so an OR, even inside one raw Where, cannot bypass a scope
+	conditions := make([]string, 0, len(scopes)+1)
+	if len(b.WhereList) > 0 {
+		conditions = append(conditions, "("+sb.String()+")")
+	}
+	conditions = append(conditions, scopes...)
+	return " WHERE " + strings.Join(conditions, " AND "), nil
+}
+
+// * collapse the user conditions into one parenthesized AND term,
+// * so a condition appended afterwards cannot be bypassed by an OR
+func (b *Builder) groupWhere() {
+	if len(b.WhereList) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	for i, e := range b.WhereList {
+		if i > 0 {
+			sb.WriteString(" ")
+			sb.WriteString(e.Operator)
+			sb.WriteString(" ")
+		}
+		sb.WriteString(e.Condition)
+	}
+	b.WhereList = []Where{{Condition: "(" + sb.String() + ")", Operator: "AND"}}
 }
 
 func (b *Builder) Where(condition string, args ...any) *Builder {
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"fmt"
+)
+
+const (
+	excludeTrashed trashed = iota
+	withTrashed
+	onlyTrashed
+)
+
+// * Delete on table sets column to the current time, reads skip rows where it is set
+func (d *Connector) SoftDelete(table, column string) error {
+	if err := ValidateColumn(table); err != nil {
+		return err
+	}
+	if err := ValidateColumn(column); err != nil {
+		return err
+	}
+
+	d.Write.Tables.update(table, func(opts *tableOptions) {
+		opts.softDelete = column
+	})
+	return nil
+}
+
+func (b *Builder) WithTrashed() *Builder {
+	b.TrashedMode = withTrashed
+	return b
+}
+
+func (b *Builder) OnlyTrashed() *Builder {
+	b.TrashedMode = onlyTrashed
+	return b
+}
+
+func (b *Builder) softDeleteColumn() (string, bool) {
+	opts, ok := b.tableOptions()
+	if !ok || opts.softDelete == "" {
+		return "", false
+	}
+	return opts.softDelete, true
+}
+
+// * follows the table's Timestamps format when registered
+func (b *Builder) deletedAt() string {
+	if opts, ok := b.tableOptions(); ok && opts.timestamps != nil {
+		return literal(opts.timestamps.value())
+	}
+	return "CURRENT_TIMESTAMP"
+}
+
+// * clears the soft delete column on trashed rows matching the WHERE
+func (b *Builder) Restore() (int64, error) {
+	column, ok := b.softDeleteColumn()
+	if !ok {
+		defer builderClear(b)
+		return 0, fmt.Errorf("Restore: table is not registered for soft delete")
+	}
+
+	b.TrashedMode = onlyTrashed
+	b.UpdateList = append(b.UpdateList, fmt.Sprintf("%s = NULL", quote(column)))
+	return b.Update()
+}
+
+// * removes rows for good, trashed rows included unless OnlyTrashed narrows it
+func (b *Builder) ForceDelete(force ...bool) (int64, error) {
+	if b.TrashedMode == excludeTrashed {
+		b.TrashedMode = withTrashed
+	}
+	return b.delete(force...)
+}
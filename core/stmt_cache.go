@@ -0,0 +1,174 @@
+package core
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type stmtCache struct {
+	mu        sync.Mutex
+	db        *sql.DB
+	size      int
+	list      *list.List
+	items     map[string]*list.Element
+	schema    *atomic.Uint64
+	version   uint64
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type stmtEntry struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+type CacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Size      int   `json:"size"`
+	Capacity  int   `json:"capacity"`
+}
+
+func newStmtCache(db *sql.DB, size int, schema *atomic.Uint64) *stmtCache {
+	return &stmtCache{
+		db:     db,
+		size:   size,
+		list:   list.New(),
+		items:  make(map[string]*list.Element, size),
+		schema: schema,
+	}
+}
+
+// * statements stay open while referenced, release closes evicted ones
+func (c *stmtCache) acquire(ctx context.Context, query string) (*stmtEntry, error) {
+	c.mu.Lock()
+	c.checkSchema()
+	if el, ok := c.items[query]; ok {
+		c.list.MoveToFront(el)
+		entry := el.Value.(*stmtEntry)
+		entry.refs++
+		c.hits++
+		c.mu.Unlock()
+		return entry, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[query]; ok {
+		stmt.Close()
+		entry := el.Value.(*stmtEntry)
+		entry.refs++
+		return entry, nil
+	}
+
+	entry := &stmtEntry{query: query, stmt: stmt, refs: 1}
+	c.items[query] = c.list.PushFront(entry)
+	for c.list.Len() > c.size {
+		c.evict(c.list.Back())
+	}
+	return entry, nil
+}
+
+func (c *stmtCache) release(entry *stmtEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
+
+func (c *stmtCache) evict(el *list.Element) {
+	entry := el.Value.(*stmtEntry)
+	c.list.Remove(el)
+	delete(c.items, entry.query)
+	c.evictions++
+
+	entry.evicted = true
+	if entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
+
+func (c *stmtCache) checkSchema() {
+	if c.schema == nil {
+		return
+	}
+	if version := c.schema.Load(); version != c.version {
+		c.version = version
+		c.clear()
+	}
+}
+
+func (c *stmtCache) clear() {
+	for el := c.list.Front(); el != nil; {
+		next := el.Next()
+		c.evict(el)
+		el = next
+	}
+}
+
+func (c *stmtCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.list.Len(),
+		Capacity:  c.size,
+	}
+}
+
+func (b *Builder) CacheStats() CacheStats {
+	if b.Cache == nil {
+		return CacheStats{}
+	}
+	return b.Cache.stats()
+}
+
+func (b *Builder) ClearCache() {
+	if b.Cache == nil {
+		return
+	}
+	b.Cache.mu.Lock()
+	defer b.Cache.mu.Unlock()
+
+	b.Cache.clear()
+}
+
+func isSchemaChange(query string) bool {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	for _, prefix := range []string{"CREATE ", "DROP ", "ALTER "} {
+		if strings.HasPrefix(query, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// * bump the shared schema version so every cache on the connector drops its statements
+func (b *Builder) schemaChanged(query string) {
+	if b.Schema == nil || !isSchemaChange(query) {
+		return
+	}
+	b.Schema.Add(1)
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+// * per-table behaviour registered on the Connector, shared by Read and Write
+type tableRegistry struct {
+	mu     sync.RWMutex
+	tables map[string]*tableOptions
+}
+
+type tableOptions struct {
+	softDelete string
+	timestamps *Timestamps
+	scopes     []Scope
+}
+
+func newTableRegistry() *tableRegistry {
+	return &tableRegistry{tables: make(map[string]*tableOptions)}
+}
+
+func (r *tableRegistry) update(table string, fn func(*tableOptions)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	opts, ok := r.tables[table]
+	if !ok {
+		opts = &tableOptions{}
+		r.tables[table] = opts
+	}
+	fn(opts)
+}
+
+// * returns a copy so callers never read while a registration is writing
+func (r *tableRegistry) get(table string) (tableOptions, bool) {
+	if r == nil {
+		return tableOptions{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	opts, ok := r.tables[table]
+	if !ok {
+		return tableOptions{}, false
+	}
+	return *opts, true
+}
+
+func (b *Builder) tableOptions() (tableOptions, bool) {
+	if b.TableName == nil {
+		return tableOptions{}, false
+	}
+	return b.Tables.get(*b.TableName)
+}
+
+// * conditions added after the user WHERE for Get / First / Last / Count / Update / Delete
+func (b *Builder) scopes() ([]string, []any, error) {
+	opts, ok := b.tableOptions()
+	if !ok {
+		return nil, nil, nil
+	}
+
+	var conditions []string
+	var args []any
+
+	if opts.softDelete != "" {
+		column := fmt.Sprintf("%s.%s", quote(*b.TableName), quote(opts.softDelete))
+		switch b.TrashedMode {
+		case excludeTrashed:
+			conditions = append(conditions, column+" IS NULL")
+		case onlyTrashed:
+			conditions = append(conditions, column+" IS NOT NULL")
+		}
+	}
+
+	for _, s := range b.activeScopes(opts) {
+		value, err := s.value(b.context())
+		if err != nil {
+			return nil, nil, err
+		}
+		conditions = append(conditions, fmt.Sprintf("%s.%s = ?", quote(*b.TableName), quote(s.Column)))
+		args = append(args, value)
+	}
+
+	return conditions, args, nil
+}
+
+// * scope errors are reported by buildWhere, which always runs first
+func (b *Builder) whereArgs() []any {
+	_, scopeArgs, _ := b.scopes()
+	args := make([]any, 0, len(b.WhereArgs)+len(scopeArgs))
+	args = append(args, b.WhereArgs...)
+	return append(args, scopeArgs...)
+}
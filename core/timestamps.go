@@ -0,0 +1,125 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	UnixSeconds TimeFormat = iota
+	UnixMillis
+	RFC3339
+)
+
+type TimeFormat uint32
+
+type Timestamps struct {
+	CreatedAt string           // set on Insert / InsertBatch, empty to skip
+	UpdatedAt string           // set on Insert / InsertBatch / Update / Increase / Decrease / Toggle, empty to skip
+	Format    TimeFormat       // UnixSeconds by default
+	Now       func() time.Time // time.Now when nil, replace in tests
+}
+
+// * values already present in the data are kept, soft deletes use the same format
+func (d *Connector) Timestamps(table string, t Timestamps) error {
+	if err := ValidateColumn(table); err != nil {
+		return err
+	}
+	for _, col := range []string{t.CreatedAt, t.UpdatedAt} {
+		if col == "" {
+			continue
+		}
+		if err := ValidateColumn(col); err != nil {
+			return err
+		}
+	}
+	if t.Format > RFC3339 {
+		return fmt.Errorf("invalid time format: %d", t.Format)
+	}
+	if t.Now == nil {
+		t.Now = time.Now
+	}
+
+	d.Write.Tables.update(table, func(opts *tableOptions) {
+		opts.timestamps = &t
+	})
+	return nil
+}
+
+func (t *Timestamps) value() any {
+	now := t.Now()
+	switch t.Format {
+	case UnixMillis:
+		return now.UnixMilli()
+	case RFC3339:
+		return now.UTC().Format(time.RFC3339)
+	default:
+		return now.Unix()
+	}
+}
+
+// * returns data with the timestamp columns filled, the caller's map is not modified
+func stampRow(data map[string]any, now any, columns ...string) map[string]any {
+	var stamped map[string]any
+	for _, col := range columns {
+		if col == "" {
+			continue
+		}
+		if _, ok := data[col]; ok {
+			continue
+		}
+		if stamped == nil {
+			stamped = make(map[string]any, len(data)+len(columns))
+			for k, v := range data {
+				stamped[k] = v
+			}
+		}
+		stamped[col] = now
+	}
+	if stamped == nil {
+		return data
+	}
+	return stamped
+}
+
+func (b *Builder) stampInsert(data []map[string]any) []map[string]any {
+	opts, ok := b.tableOptions()
+	if !ok || opts.timestamps == nil {
+		return data
+	}
+
+	now := opts.timestamps.value()
+	stamped := make([]map[string]any, len(data))
+	for i, row := range data {
+		stamped[i] = stampRow(row, now, opts.timestamps.CreatedAt, opts.timestamps.UpdatedAt)
+	}
+	return stamped
+}
+
+func (b *Builder) stampUpdate(data map[string]any) (string, any, bool) {
+	opts, ok := b.tableOptions()
+	if !ok || opts.timestamps == nil || opts.timestamps.UpdatedAt == "" {
+		return "", nil, false
+	}
+	if _, ok := data[opts.timestamps.UpdatedAt]; ok {
+		return "", nil, false
+	}
+	return opts.timestamps.UpdatedAt, opts.timestamps.value(), true
+}
+
+// * DO UPDATE is an update, updated_at takes the value stamped on the proposed row unless the caller sets it
+func (b *Builder) stampUpsert(setList []string) (string, bool) {
+	opts, ok := b.tableOptions()
+	if !ok || opts.timestamps == nil || opts.timestamps.UpdatedAt == "" {
+		return "", false
+	}
+
+	column := opts.timestamps.UpdatedAt
+	for _, set := range setList {
+		if strings.HasPrefix(set, quote(column)+" ") {
+			return "", false
+		}
+	}
+	return column, true
+}
@@ -59,6 +59,10 @@ func (b *Builder) Update(data ...map[string]any) (int64, error) {
 		return 0, err
 	}
 
+	if len(b.ReturningList) > 0 {
+		return b.queryReturning(query, values...)
+	}
+
 	result, err := b.ExecAutoAsignContext(query, values...)
 	if err != nil {
 		return 0, err
@@ -112,10 +116,27 @@ func updateBuilder(b *Builder, data ...map[string]any) (string, []any, error) {
 		}
 	}
 
+	if column, now, ok := b.stampUpdate(mainData); ok {
+		parts = append(parts, fmt.Sprintf("%s = ?", quote(column)))
+		values = append(values, now)
+	}
+
+	assignments, scopeArgs, err := b.scopeAssignments()
+	if err != nil {
+		return "", []any{}, err
+	}
+	parts = append(parts, assignments...)
+	values = append(values, scopeArgs...)
+
+	where, err := b.buildWhere()
+	if err != nil {
+		return "", []any{}, err
+	}
+
 	sb.WriteString(strings.Join(parts, ", "))
-	sb.WriteString(b.buildWhere())
+	sb.WriteString(where)
 
-	values = append(values, b.WhereArgs...)
+	values = append(values, b.whereArgs()...)
 
 	return sb.String(), values, nil
 }
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// * reference the value proposed for insertion, rendered as excluded."column"
+type Excluded string
+
+func (b *Builder) upsert() *upsert {
+	if b.WithUpsert == nil {
+		b.WithUpsert = &upsert{}
+	}
+	return b.WithUpsert
+}
+
+func (b *Builder) OnConflict(columns ...string) *Builder {
+	u := b.upsert()
+	for _, col := range columns {
+		if err := ValidateColumn(col); err != nil {
+			b.Error = append(b.Error, fmt.Errorf("OnConflict: %w", err))
+			return b
+		}
+		u.Target = append(u.Target, quote(col))
+	}
+	return b
+}
+
+func (b *Builder) DoNothing() *Builder {
+	b.upsert().Nothing = true
+	return b
+}
+
+func (b *Builder) DoUpdate(data map[string]any) *Builder {
+	u := b.upsert()
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		if err := ValidateColumn(key); err != nil {
+			b.Error = append(b.Error, fmt.Errorf("DoUpdate: %w", err))
+			return b
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if ex, ok := data[key].(Excluded); ok {
+			if err := ValidateColumn(string(ex)); err != nil {
+				b.Error = append(b.Error, fmt.Errorf("DoUpdate: %w", err))
+				return b
+			}
+			u.SetList = append(u.SetList, fmt.Sprintf("%s = excluded.%s", quote(key), quote(string(ex))))
+			continue
+		}
+		u.SetList = append(u.SetList, fmt.Sprintf("%s = ?", quote(key)))
+		u.SetArgs = append(u.SetArgs, data[key])
+	}
+	return b
+}
+
+func (b *Builder) DoUpdateExcluded(columns ...string) *Builder {
+	data := make(map[string]any, len(columns))
+	for _, col := range columns {
+		data[col] = Excluded(col)
+	}
+	return b.DoUpdate(data)
+}
+
+func (b *Builder) DoUpdateSet(expression string, args ...any) *Builder {
+	u := b.upsert()
+	u.SetList = append(u.SetList, expression)
+	u.SetArgs = append(u.SetArgs, args...)
+	return b
+}
+
+func (b *Builder) DoUpdateWhere(condition string, args ...any) *Builder {
+	u := b.upsert()
+	u.WhereList = append(u.WhereList, condition)
+	u.WhereArgs = append(u.WhereArgs, args...)
+	return b
+}
+
+func (b *Builder) buildUpsert() (string, []any, error) {
+	u := b.WithUpsert
+	if u == nil {
+		return "", nil, nil
+	}
+
+	if u.Nothing && len(u.SetList) > 0 {
+		return "", nil, fmt.Errorf("OnConflict: cannot combine DO NOTHING with DO UPDATE")
+	}
+
+	if !u.Nothing && len(u.SetList) == 0 {
+		return "", nil, fmt.Errorf("OnConflict: DoNothing or DoUpdate is required")
+	}
+
+	if len(u.WhereList) > 0 && u.Nothing {
+		return "", nil, fmt.Errorf("OnConflict: DoUpdateWhere requires DoUpdate")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(" ON CONFLICT")
+	if len(u.Target) > 0 {
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(u.Target, ", "))
+		sb.WriteString(")")
+	}
+
+	if u.Nothing {
+		sb.WriteString(" DO NOTHING")
+		return sb.String(), nil, nil
+	}
+
+	// * the conflicting row may belong to another scope, it is only updated inside the current one
+	assignments, scopeArgs, err := b.scopeAssignments()
+	if err != nil {
+		return "", nil, err
+	}
+
+	setList := slices.Clone(u.SetList)
+	if column, ok := b.stampUpsert(setList); ok {
+		setList = append(setList, fmt.Sprintf("%s = excluded.%s", quote(column), quote(column)))
+	}
+
+	sb.WriteString(" DO UPDATE SET ")
+	sb.WriteString(strings.Join(append(setList, assignments...), ", "))
+
+	args := append([]any{}, u.SetArgs...)
+	args = append(args, scopeArgs...)
+
+	where := slices.Clone(u.WhereList)
+	if len(where) > 0 && len(assignments) > 0 {
+		where = []string{"(" + strings.Join(where, " AND ") + ")"}
+	}
+	for _, assignment := range assignments {
+		where = append(where, fmt.Sprintf("%s.%s", quote(*b.TableName), assignment))
+	}
+	if len(where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(where, " AND "))
+		args = append(args, u.WhereArgs...)
+		args = append(args, scopeArgs...)
+	}
+
+	return sb.String(), args, nil
+}
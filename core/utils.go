@@ -1,11 +1,15 @@
 package core
 
 import (
+	"database/sql/driver"
 	_ "embed"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 //go:embed sql_keywords.json
@@ -15,6 +19,8 @@ var (
 	columnRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
 	keyMap      map[string]bool
 	maxLength   = 128
+	// * SQLITE_MAX_VARIABLE_NUMBER default since 3.32.0
+	maxVariables = 32766
 )
 
 func init() {
@@ -33,6 +39,10 @@ func quote(name string) string {
 	return fmt.Sprintf(`"%s"`, name)
 }
 
+func quoteString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
 func ValidateColumn(name string) error {
 	if len(name) == 0 {
 		return fmt.Errorf("identifier is required")
@@ -53,6 +63,62 @@ func ValidateColumn(name string) error {
 	return nil
 }
 
+// * replace ? placeholders outside quoted strings and identifiers with escaped literals
+func Interpolate(query string, args []any) string {
+	var sb strings.Builder
+	var quoteChar byte
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quoteChar != 0:
+			if c == quoteChar {
+				quoteChar = 0
+			}
+		case c == '\'' || c == '"':
+			quoteChar = c
+		case c == '?' && n < len(args):
+			sb.WriteString(literal(args[n]))
+			n++
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+func literal(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quoteString(val)
+	case []byte:
+		return "X'" + hex.EncodeToString(val) + "'"
+	case bool:
+		if val {
+			return "1"
+		}
+		return "0"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", val)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case time.Time:
+		return quoteString(val.Format(time.RFC3339Nano))
+	case driver.Valuer:
+		inner, err := val.Value()
+		if err != nil {
+			return "NULL"
+		}
+		return literal(inner)
+	default:
+		return quoteString(fmt.Sprint(val))
+	}
+}
+
 func FormatValue(v any) string {
 	switch val := v.(type) {
 	case string:
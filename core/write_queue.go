@@ -0,0 +1,212 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type GroupCommit struct {
+	MaxBatch   int           `json:"max_batch,omitempty"`   // writes per transaction, 0 disables group commit
+	MaxLatency time.Duration `json:"max_latency,omitempty"` // longest a write waits for others to join, default 2ms
+}
+
+type GroupCommitStats struct {
+	Batches int64 `json:"batches"`
+	Writes  int64 `json:"writes"`
+}
+
+type writeJob struct {
+	ctx   context.Context
+	query string
+	args  []any
+	done  chan writeResult
+}
+
+type writeResult struct {
+	result sql.Result
+	err    error
+}
+
+// * single goroutine owning the write connection, small writes share one transaction
+type writeQueue struct {
+	builder    *Builder
+	jobs       chan *writeJob
+	maxBatch   int
+	maxLatency time.Duration
+	done       chan struct{}
+	stopped    chan struct{}
+	closeOnce  sync.Once
+	batches    atomic.Int64
+	writes     atomic.Int64
+}
+
+func newWriteQueue(b *Builder, c GroupCommit) *writeQueue {
+	if c.MaxBatch <= 0 {
+		return nil
+	}
+	if c.MaxLatency <= 0 {
+		c.MaxLatency = 2 * time.Millisecond
+	}
+
+	q := &writeQueue{
+		builder:    b,
+		jobs:       make(chan *writeJob),
+		maxBatch:   c.MaxBatch,
+		maxLatency: c.MaxLatency,
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// * only plain DML can share a transaction, DDL / PRAGMA / VACUUM run directly
+func isGroupable(query string) bool {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	for _, prefix := range []string{"INSERT ", "UPDATE ", "DELETE ", "REPLACE "} {
+		if strings.HasPrefix(query, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *writeQueue) exec(ctx context.Context, query string, args []any) (sql.Result, error) {
+	job := &writeJob{
+		ctx:   ctx,
+		query: query,
+		args:  args,
+		done:  make(chan writeResult, 1),
+	}
+
+	select {
+	case q.jobs <- job:
+	case <-q.done:
+		return nil, fmt.Errorf("write queue is closed")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// * once accepted the job always gets a result, a cancelled context is checked before it runs
+	r := <-job.done
+	return r.result, r.err
+}
+
+func (q *writeQueue) run() {
+	defer close(q.stopped)
+
+	for {
+		var first *writeJob
+		select {
+		case first = <-q.jobs:
+		case <-q.done:
+			return
+		}
+
+		batch := []*writeJob{first}
+		timer := time.NewTimer(q.maxLatency)
+	collect:
+		for len(batch) < q.maxBatch {
+			select {
+			case job := <-q.jobs:
+				batch = append(batch, job)
+			case <-timer.C:
+				break collect
+			case <-q.done:
+				break collect
+			}
+		}
+		timer.Stop()
+
+		q.commit(batch)
+	}
+}
+
+// * each job runs inside its own savepoint so one failure does not discard the batch
+// * statements run on the batch context, a job context cancelled mid-statement would interrupt the whole transaction
+func (q *writeQueue) commit(batch []*writeJob) {
+	results := make([]writeResult, len(batch))
+	defer func() {
+		for i, job := range batch {
+			job.done <- results[i]
+		}
+	}()
+
+	ctx := context.Background()
+	tx, err := q.builder.beginTx(ctx)
+	if err != nil {
+		failBatch(results, err)
+		return
+	}
+
+	for i, job := range batch {
+		if err := job.ctx.Err(); err != nil {
+			results[i].err = err
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT write_job"); err != nil {
+			tx.Rollback()
+			failBatch(results, wrapError(err))
+			return
+		}
+
+		result, err := tx.ExecContext(ctx, job.query, job.args...)
+		if err != nil {
+			if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO write_job"); rbErr != nil {
+				tx.Rollback()
+				failBatch(results, wrapError(rbErr))
+				return
+			}
+		}
+		if _, relErr := tx.ExecContext(ctx, "RELEASE write_job"); relErr != nil {
+			tx.Rollback()
+			failBatch(results, wrapError(relErr))
+			return
+		}
+		results[i] = writeResult{result: result, err: wrapError(err)}
+	}
+
+	if err := tx.Commit(); err != nil {
+		failBatch(results, wrapError(err))
+		return
+	}
+
+	q.batches.Add(1)
+	q.writes.Add(int64(len(batch)))
+}
+
+// * nothing in the batch was committed, jobs without their own error get err
+func failBatch(results []writeResult, err error) {
+	for i := range results {
+		if results[i].err == nil {
+			results[i] = writeResult{err: err}
+		}
+	}
+}
+
+func (q *writeQueue) stats() GroupCommitStats {
+	if q == nil {
+		return GroupCommitStats{}
+	}
+	return GroupCommitStats{
+		Batches: q.batches.Load(),
+		Writes:  q.writes.Load(),
+	}
+}
+
+// * pending jobs already accepted are committed before the goroutine exits
+func (q *writeQueue) close() {
+	if q == nil {
+		return
+	}
+	q.closeOnce.Do(func() {
+		close(q.done)
+	})
+	<-q.stopped
+}
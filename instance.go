@@ -1,6 +1,7 @@
 package goSqlite
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -82,19 +83,18 @@ func New(c core.Config) (*core.Connector, error) {
 		return nil, fmt.Errorf("failed to ping read db: %w", err)
 	}
 
+	conn := core.NewConnector(read, write, c)
+
 	go func() {
 		ticker := time.NewTicker(30 * time.Second)
 		defer ticker.Stop()
 
 		for range ticker.C {
-			if _, err := write.Exec("PRAGMA wal_checkpoint(PASSIVE)"); err != nil {
+			if err := conn.Checkpoint(context.Background(), "PASSIVE"); err != nil {
 				fmt.Printf("checkpoint failed: %v\n", err)
 			}
 		}
 	}()
 
-	return &core.Connector{
-		Read:  core.NewBuilder(read),
-		Write: core.NewBuilder(write),
-	}, nil
+	return conn, nil
 }
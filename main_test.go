@@ -1,6 +1,10 @@
 package goSqlite
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -155,3 +159,97 @@ func TestNewIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestConnectorStats(t *testing.T) {
+	conn, err := New(core.Config{Path: filepath.Join(t.TempDir(), "stats.db")})
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer conn.Close()
+
+	conn.Write.Table("users").Create(
+		core.Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		core.Column{Name: "name", Type: "TEXT"},
+	)
+	conn.Write.Table("users").Insert(map[string]any{"name": "Alice"})
+
+	t.Run("Ping", func(t *testing.T) {
+		if err := conn.Ping(context.Background()); err != nil {
+			t.Errorf("expected ping to succeed, got %v", err)
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		stats, err := conn.Stats()
+		if err != nil {
+			t.Fatalf("stats failed: %v", err)
+		}
+		if stats.Write.MaxOpenConnections != 1 || stats.Read.MaxOpenConnections != 50 {
+			t.Errorf("unexpected pool limits: read %d, write %d",
+				stats.Read.MaxOpenConnections, stats.Write.MaxOpenConnections)
+		}
+		if stats.PageCount < 2 {
+			t.Errorf("expected page count >= 2, got %d", stats.PageCount)
+		}
+		if stats.WALSize <= 0 {
+			t.Errorf("expected WAL to hold uncheckpointed frames, got %d", stats.WALSize)
+		}
+		if stats.LastCheckpoint != nil {
+			t.Errorf("expected no checkpoint yet, got %+v", stats.LastCheckpoint)
+		}
+	})
+
+	t.Run("Checkpoint result", func(t *testing.T) {
+		if err := conn.Checkpoint(context.Background(), "truncate"); err != nil {
+			t.Fatalf("checkpoint failed: %v", err)
+		}
+
+		stats, err := conn.Stats()
+		if err != nil {
+			t.Fatalf("stats failed: %v", err)
+		}
+		last := stats.LastCheckpoint
+		if last == nil || last.Mode != "TRUNCATE" || last.Busy || last.Error != "" {
+			t.Fatalf("unexpected checkpoint result: %+v", last)
+		}
+		if stats.WALSize != 0 {
+			t.Errorf("expected truncated WAL, got %d bytes", stats.WALSize)
+		}
+	})
+
+	t.Run("Invalid checkpoint mode", func(t *testing.T) {
+		if err := conn.Checkpoint(context.Background(), "NOW"); err == nil {
+			t.Error("expected error for invalid mode")
+		}
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		conn.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+
+		var body struct {
+			Status string      `json:"status"`
+			Stats  *core.Stats `json:"stats"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if body.Status != "ok" || body.Stats == nil || body.Stats.LastCheckpoint == nil {
+			t.Errorf("unexpected body: %s", rec.Body.String())
+		}
+	})
+
+	t.Run("Handler after close", func(t *testing.T) {
+		conn.Close()
+
+		rec := httptest.NewRecorder()
+		conn.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected 503, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package otelsqlite
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pardnchiu/go-sqlite/core"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scope = "github.com/pardnchiu/go-sqlite/otelsqlite"
+
+var tableRegex = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE|TABLE(?: IF NOT EXISTS)?)\s+"?([A-Za-z_][A-Za-z0-9_]*)"?`)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+type Option func(*config)
+
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+type Instrumentation struct {
+	tracer       trace.Tracer
+	duration     metric.Float64Histogram
+	checkpoint   metric.Float64Histogram
+	registration metric.Registration
+}
+
+type spanKey struct{}
+
+// * registers the tracing hook on both pools and observes pool and WAL state
+func Instrument(conn *core.Connector, opts ...Option) (*Instrumentation, error) {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	meter := c.meterProvider.Meter(scope)
+	i := &Instrumentation{
+		tracer: c.tracerProvider.Tracer(scope),
+	}
+
+	var err error
+	if i.duration, err = meter.Float64Histogram("db.client.operation.duration",
+		metric.WithDescription("Duration of database client operations"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+
+	if i.checkpoint, err = meter.Float64Histogram("sqlite.wal.checkpoint.duration",
+		metric.WithDescription("Duration of WAL checkpoints"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+
+	waitTime, err := meter.Float64ObservableCounter("db.client.connections.wait_time",
+		metric.WithDescription("Total time blocked waiting for a new connection"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	waitCount, err := meter.Int64ObservableCounter("db.client.connections.wait_count",
+		metric.WithDescription("Total number of connections waited for"))
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := meter.Int64ObservableGauge("db.client.connections.usage",
+		metric.WithDescription("Connections by state"))
+	if err != nil {
+		return nil, err
+	}
+
+	walSize, err := meter.Int64ObservableGauge("sqlite.wal.size",
+		metric.WithDescription("Size of the WAL file"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+
+	pools := map[string]*sql.DB{
+		"read":  conn.Read.DB,
+		"write": conn.Write.DB,
+	}
+
+	i.registration, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		for name, db := range pools {
+			stats := db.Stats()
+			pool := attribute.String("db.client.connections.pool.name", name)
+			o.ObserveFloat64(waitTime, stats.WaitDuration.Seconds(), metric.WithAttributes(pool))
+			o.ObserveInt64(waitCount, stats.WaitCount, metric.WithAttributes(pool))
+			o.ObserveInt64(usage, int64(stats.InUse), metric.WithAttributes(pool, attribute.String("state", "used")))
+			o.ObserveInt64(usage, int64(stats.Idle), metric.WithAttributes(pool, attribute.String("state", "idle")))
+		}
+
+		if stats, err := conn.StatsContext(ctx); err == nil {
+			o.ObserveInt64(walSize, stats.WALSize)
+		}
+		return nil
+	}, waitTime, waitCount, usage, walSize)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.Use(i)
+	return i, nil
+}
+
+// * stops metric callbacks, the hook stays registered on the connector
+func (i *Instrumentation) Close() error {
+	return i.registration.Unregister()
+}
+
+func (i *Instrumentation) BeforeQuery(ctx context.Context, query string, args []any) context.Context {
+	operation, table := parse(query)
+
+	name := operation
+	if table != "" {
+		name += " " + table
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "sqlite"),
+		attribute.String("db.statement", query),
+		attribute.String("db.operation", operation),
+	}
+	if table != "" {
+		attrs = append(attrs, attribute.String("db.sql.table", table))
+	}
+
+	ctx, span := i.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+func (i *Instrumentation) AfterQuery(ctx context.Context, query string, args []any, duration time.Duration, rowsAffected int64, err error) {
+	operation, table := parse(query)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "sqlite"),
+		attribute.String("db.operation", operation),
+	}
+	if table != "" {
+		attrs = append(attrs, attribute.String("db.sql.table", table))
+	}
+	i.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+
+	if strings.HasPrefix(strings.ToUpper(query), "PRAGMA WAL_CHECKPOINT") {
+		i.checkpoint.Record(ctx, duration.Seconds())
+	}
+
+	span, ok := ctx.Value(spanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if rowsAffected >= 0 {
+		span.SetAttributes(attribute.Int64("db.rows_affected", rowsAffected))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func parse(query string) (string, string) {
+	query = strings.TrimSpace(query)
+	operation, _, _ := strings.Cut(query, " ")
+	operation = strings.ToUpper(operation)
+
+	if operation == "PRAGMA" || operation == "EXPLAIN" {
+		return operation, ""
+	}
+
+	if match := tableRegex.FindStringSubmatch(query); match != nil {
+		return operation, match[1]
+	}
+	return operation, ""
+}
@@ -0,0 +1,180 @@
+package otelsqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	goSqlite "github.com/pardnchiu/go-sqlite"
+	"github.com/pardnchiu/go-sqlite/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setup(t *testing.T) (*core.Connector, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	conn, err := goSqlite.New(core.Config{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(conn.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	i, err := Instrument(conn, WithTracerProvider(tp), WithMeterProvider(mp))
+	if err != nil {
+		t.Fatalf("failed to instrument: %v", err)
+	}
+	t.Cleanup(func() { i.Close() })
+
+	return conn, exporter, reader
+}
+
+func attr(attrs []attribute.KeyValue, key string) string {
+	for _, kv := range attrs {
+		if string(kv.Key) == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("failed to collect: %v", err)
+	}
+
+	result := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m
+		}
+	}
+	return result
+}
+
+func TestSpans(t *testing.T) {
+	conn, exporter, _ := setup(t)
+
+	conn.Write.Table("users").Create(
+		core.Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		core.Column{Name: "name", Type: "TEXT"},
+	)
+	exporter.Reset()
+
+	t.Run("Insert span attributes", func(t *testing.T) {
+		if _, err := conn.Write.Table("users").Insert(map[string]any{"name": "a"}); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+
+		spans := exporter.GetSpans()
+		if len(spans) != 1 {
+			t.Fatalf("expected 1 span, got %d", len(spans))
+		}
+
+		span := spans[0]
+		if span.Name != "INSERT users" {
+			t.Errorf("expected span name 'INSERT users', got %q", span.Name)
+		}
+		if attr(span.Attributes, "db.system") != "sqlite" ||
+			attr(span.Attributes, "db.operation") != "INSERT" ||
+			attr(span.Attributes, "db.sql.table") != "users" ||
+			attr(span.Attributes, "db.rows_affected") != "1" {
+			t.Errorf("unexpected attributes: %v", span.Attributes)
+		}
+		if attr(span.Attributes, "db.statement") == "" {
+			t.Error("expected db.statement attribute")
+		}
+	})
+
+	t.Run("Failed query marks span error", func(t *testing.T) {
+		exporter.Reset()
+		conn.Read.Table("missing").Count()
+
+		spans := exporter.GetSpans()
+		if len(spans) != 1 || spans[0].Status.Code != codes.Error {
+			t.Errorf("expected 1 error span, got %+v", spans)
+		}
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	conn, _, reader := setup(t)
+
+	conn.Write.Table("users").Create(
+		core.Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		core.Column{Name: "name", Type: "TEXT"},
+	)
+	conn.Write.Table("users").Insert(map[string]any{"name": "a"})
+	conn.Read.Table("users").Count()
+
+	if err := conn.Checkpoint(context.Background(), "PASSIVE"); err != nil {
+		t.Fatalf("checkpoint failed: %v", err)
+	}
+
+	metrics := collect(t, reader)
+
+	for _, name := range []string{
+		"db.client.operation.duration",
+		"sqlite.wal.checkpoint.duration",
+		"db.client.connections.wait_time",
+		"db.client.connections.wait_count",
+		"db.client.connections.usage",
+		"sqlite.wal.size",
+	} {
+		if _, ok := metrics[name]; !ok {
+			t.Errorf("expected metric %s", name)
+		}
+	}
+
+	if hist, ok := metrics["db.client.operation.duration"].Data.(metricdata.Histogram[float64]); ok {
+		var count uint64
+		for _, dp := range hist.DataPoints {
+			count += dp.Count
+		}
+		if count < 3 {
+			t.Errorf("expected at least 3 recorded operations, got %d", count)
+		}
+	} else {
+		t.Error("expected float64 histogram")
+	}
+
+	if gauge, ok := metrics["sqlite.wal.size"].Data.(metricdata.Gauge[int64]); ok {
+		if len(gauge.DataPoints) != 1 || gauge.DataPoints[0].Value <= 0 {
+			t.Errorf("expected positive WAL size, got %+v", gauge.DataPoints)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query     string
+		operation string
+		table     string
+	}{
+		{`SELECT * FROM "users" WHERE "id" = ?`, "SELECT", "users"},
+		{`INSERT OR IGNORE INTO "users" ("name") VALUES (?)`, "INSERT", "users"},
+		{`UPDATE "users" SET "name" = ?`, "UPDATE", "users"},
+		{`DELETE FROM "users"`, "DELETE", "users"},
+		{`CREATE TABLE IF NOT EXISTS "users" ("id" INTEGER)`, "CREATE", "users"},
+		{`PRAGMA wal_checkpoint(PASSIVE)`, "PRAGMA", ""},
+	}
+
+	for _, tt := range tests {
+		operation, table := parse(tt.query)
+		if operation != tt.operation || table != tt.table {
+			t.Errorf("parse(%q) = %q, %q, expected %q, %q", tt.query, operation, table, tt.operation, tt.table)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package sqlitetest
+
+import (
+	"testing"
+
+	"github.com/pardnchiu/go-sqlite/core"
+)
+
+func explain(t testing.TB, b *core.Builder) *core.Plan {
+	t.Helper()
+
+	plan, err := b.Explain()
+	if err != nil {
+		t.Fatalf("failed to explain query: %v", err)
+	}
+	return plan
+}
+
+func AssertUsesIndex(t testing.TB, b *core.Builder, index string) {
+	t.Helper()
+
+	plan := explain(t, b)
+	if plan == nil {
+		return
+	}
+
+	if !plan.UsesIndex(index) {
+		query, _, _ := b.ToSQL()
+		t.Errorf("expected index %s to be used\nquery: %s\nplan:\n%s", index, query, plan)
+	}
+}
+
+func AssertNoFullScan(t testing.TB, b *core.Builder) {
+	t.Helper()
+
+	plan := explain(t, b)
+	if plan == nil {
+		return
+	}
+
+	if scans := plan.FullScans(); len(scans) > 0 {
+		query, _, _ := b.ToSQL()
+		t.Errorf("expected no full table scan, found %d on %s\nquery: %s\nplan:\n%s",
+			len(scans), scans[0].Table, query, plan)
+	}
+}
@@ -0,0 +1,100 @@
+package sqlitetest
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pardnchiu/go-sqlite/core"
+)
+
+type recorder struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed = true
+	r.message = fmt.Sprintf(format, args...)
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func setupTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+
+	err = core.NewBuilder(db).Table("users").Create(
+		core.Column{Name: "id", Type: "INTEGER", IsPrimary: true, AutoIncrease: true},
+		core.Column{Name: "email", Type: "TEXT"},
+		core.Column{Name: "name", Type: "TEXT"},
+	)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	if _, err := db.Exec(`CREATE INDEX "idx_users_email" ON "users" ("email")`); err != nil {
+		t.Fatalf("failed to create index: %v", err)
+	}
+	return db
+}
+
+func TestAssertUsesIndex(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	t.Run("Indexed query passes", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertUsesIndex(r, core.NewBuilder(db).Table("users").WhereEq("email", "a@x"), "idx_users_email")
+		if r.failed {
+			t.Errorf("expected pass, got: %s", r.message)
+		}
+	})
+
+	t.Run("Unindexed query fails", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertUsesIndex(r, core.NewBuilder(db).Table("users").WhereEq("name", "a"), "idx_users_email")
+		if !r.failed {
+			t.Error("expected failure for missing index")
+		}
+	})
+
+	t.Run("Invalid query fails", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertUsesIndex(r, core.NewBuilder(db).Table("invalid-table"), "idx_users_email")
+		if !r.failed {
+			t.Error("expected failure for invalid query")
+		}
+	})
+}
+
+func TestAssertNoFullScan(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	t.Run("Primary key lookup passes", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertNoFullScan(r, core.NewBuilder(db).Table("users").WhereEq("id", 1))
+		if r.failed {
+			t.Errorf("expected pass, got: %s", r.message)
+		}
+	})
+
+	t.Run("Table scan fails", func(t *testing.T) {
+		r := &recorder{TB: t}
+		AssertNoFullScan(r, core.NewBuilder(db).Table("users").WhereEq("name", "a"))
+		if !r.failed {
+			t.Error("expected failure for full scan")
+		}
+	})
+}